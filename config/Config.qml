@@ -45,13 +45,17 @@ QtObject {
     property int fontSizeLarge: 14
     property int fontSizeSmall: 10
 
-    // Colors - Loaded from theme.json (generated by matugen)
+    // Colors - Loaded from theme.json (generated by hecate)
     property color textColor: "#e9e1df"
     property color textColorDim: "#d4c3bf"
     property color accentColor: "#e8bdb3"
     property color backgroundColor: "#161312"
     property color backgroundColorAlt: "#221f1e"
     property color borderColor: "#9d8d8a"
+    property color errorColor: "#ffb4ab"
+    property color successColor: "#a0d756"
+    property color warningColor: "#ffb86a"
+    property color infoColor: "#afc6ff"
 
     // Spacing & Padding
     property int paddingSmall: 4
@@ -104,6 +108,13 @@ QtObject {
             config.textColor = theme.surfaceText
             config.textColorDim = theme.surfaceVariantText
             config.accentColor = theme.primary
+
+            // Semantic colors. Older theme.json files have no success (their warning and
+            // info are secondary and primary), so it keeps the default there.
+            config.errorColor = theme.error
+            config.warningColor = theme.warning
+            config.infoColor = theme.info
+            if (theme.success) config.successColor = theme.success
         } catch (e) {
             console.error("Failed to parse theme.json:", e)
         }
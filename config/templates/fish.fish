@@ -0,0 +1,33 @@
+# Fish colors - generated by HecateShell
+# Applied as universal variables after every theme change, so running shells update too
+
+set -U fish_color_normal {{colors.on_surface.default.hex_stripped}}
+set -U fish_color_command {{colors.primary.default.hex_stripped}}
+set -U fish_color_keyword {{colors.tertiary.default.hex_stripped}}
+set -U fish_color_param {{colors.on_surface.default.hex_stripped}}
+set -U fish_color_option {{colors.secondary.default.hex_stripped}}
+set -U fish_color_quote {{colors.term2.default.hex_stripped}}
+set -U fish_color_redirection {{colors.secondary.default.hex_stripped}}
+set -U fish_color_end {{colors.secondary.default.hex_stripped}}
+set -U fish_color_operator {{colors.term6.default.hex_stripped}}
+set -U fish_color_escape {{colors.term6.default.hex_stripped}}
+set -U fish_color_error {{colors.error.default.hex_stripped}}
+set -U fish_color_comment {{colors.term8.default.hex_stripped}}
+set -U fish_color_autosuggestion {{colors.term8.default.hex_stripped}}
+set -U fish_color_valid_path --underline
+set -U fish_color_cwd {{colors.primary.default.hex_stripped}}
+set -U fish_color_cwd_root {{colors.error.default.hex_stripped}}
+set -U fish_color_user {{colors.tertiary.default.hex_stripped}}
+set -U fish_color_host {{colors.secondary.default.hex_stripped}}
+set -U fish_color_cancel --reverse
+set -U fish_color_search_match --background={{colors.surface_container_highest.default.hex_stripped}}
+set -U fish_color_selection {{colors.on_secondary_container.default.hex_stripped}} --background={{colors.secondary_container.default.hex_stripped}}
+
+set -U fish_pager_color_prefix {{colors.primary.default.hex_stripped}} --bold
+set -U fish_pager_color_completion {{colors.on_surface.default.hex_stripped}}
+set -U fish_pager_color_description {{colors.on_surface_variant.default.hex_stripped}}
+set -U fish_pager_color_progress {{colors.on_primary_container.default.hex_stripped}} --background={{colors.primary_container.default.hex_stripped}}
+set -U fish_pager_color_selected_background --background={{colors.secondary_container.default.hex_stripped}}
+
+# Lets config.fish tell whether the colors were ever applied
+set -U hecate_colors_source {{colors.source_color.default.hex}}
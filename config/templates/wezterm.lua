@@ -0,0 +1,63 @@
+-- WezTerm colors - generated by HecateShell
+-- Loaded from wezterm.lua with config.colors = require("hecate-colors")
+
+return {
+  foreground = "{{colors.on_surface.default.hex}}",
+  background = "{{colors.background.default.hex}}",
+
+  cursor_bg = "{{colors.primary_container.default.hex}}",
+  cursor_fg = "{{colors.on_primary_container.default.hex}}",
+  cursor_border = "{{colors.primary_container.default.hex}}",
+
+  selection_fg = "{{colors.on_secondary_container.default.hex}}",
+  selection_bg = "{{colors.secondary_container.default.hex}}",
+
+  scrollbar_thumb = "{{colors.surface_container_highest.default.hex}}",
+  split = "{{colors.outline_variant.default.hex}}",
+
+  -- ANSI palette harmonized to the wallpaper
+  ansi = {
+    "{{colors.term0.default.hex}}",
+    "{{colors.term1.default.hex}}",
+    "{{colors.term2.default.hex}}",
+    "{{colors.term3.default.hex}}",
+    "{{colors.term4.default.hex}}",
+    "{{colors.term5.default.hex}}",
+    "{{colors.term6.default.hex}}",
+    "{{colors.term7.default.hex}}",
+  },
+  brights = {
+    "{{colors.term8.default.hex}}",
+    "{{colors.term9.default.hex}}",
+    "{{colors.term10.default.hex}}",
+    "{{colors.term11.default.hex}}",
+    "{{colors.term12.default.hex}}",
+    "{{colors.term13.default.hex}}",
+    "{{colors.term14.default.hex}}",
+    "{{colors.term15.default.hex}}",
+  },
+
+  tab_bar = {
+    background = "{{colors.surface_container.default.hex}}",
+    active_tab = {
+      bg_color = "{{colors.primary_container.default.hex}}",
+      fg_color = "{{colors.on_primary_container.default.hex}}",
+    },
+    inactive_tab = {
+      bg_color = "{{colors.surface_container.default.hex}}",
+      fg_color = "{{colors.on_surface_variant.default.hex}}",
+    },
+    inactive_tab_hover = {
+      bg_color = "{{colors.surface_container_high.default.hex}}",
+      fg_color = "{{colors.on_surface.default.hex}}",
+    },
+    new_tab = {
+      bg_color = "{{colors.surface_container.default.hex}}",
+      fg_color = "{{colors.on_surface_variant.default.hex}}",
+    },
+    new_tab_hover = {
+      bg_color = "{{colors.surface_container_high.default.hex}}",
+      fg_color = "{{colors.on_surface.default.hex}}",
+    },
+  },
+}
@@ -13,6 +13,18 @@ end
 set -x MANROFFOPT "-c"
 set -x MANPAGER "sh -c 'col -bx | bat -l man -p'"
 
+# Syntax colors follow the wallpaper: HecateShell sets them as universal variables
+# after every theme change. Apply the current ones if that never happened here.
+if status is-interactive; and not set -q hecate_colors_source; and test -f ~/.config/fish/hecate-colors.fish
+    source ~/.config/fish/hecate-colors.fish
+end
+
+# Theme colors for every new interactive shell, including terminals HecateShell has a
+# template for (the sequences match their generated colors)
+if status is-interactive; and test -f ~/.cache/hecate/sequences
+    cat ~/.cache/hecate/sequences
+end
+
 # Set settings for https://github.com/franciscolourenco/done
 set -U __done_min_cmd_duration 10000
 set -U __done_notification_urgency_level low
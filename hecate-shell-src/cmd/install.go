@@ -110,7 +110,6 @@ func runLegacyInstall(cmd *cobra.Command, args []string) error {
 	fmt.Println("     - quickshell-git (for Niri support)")
 	fmt.Println("     - cava (audio visualizer)")
 	fmt.Println("     - pipewire + wireplumber (audio control)")
-	fmt.Println("     - matugen (theme generation)")
 	fmt.Println("     - swww (wallpaper daemon)")
 	fmt.Println("     - ttf-jetbrains-mono-nerd (font)")
 	fmt.Println("\n  2. Start the shell:")
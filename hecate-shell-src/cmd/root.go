@@ -10,7 +10,7 @@ var rootCmd = &cobra.Command{
 	Long: `Hecate is a beautiful, customizable shell built with QuickShell.
 
 Features:
-  - Matugen color integration
+  - Material You color generation from wallpapers
   - Hot-reloading themes
   - Fully customizable`,
 }
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/material"
+	"hecate-shell/internal/templates"
+	"hecate-shell/internal/theme"
+
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Work with color templates",
+}
+
+var templateRenderCmd = &cobra.Command{
+	Use:   "render <file>",
+	Short: "Render a template without touching your configs",
+	Long: `Render a template and print the result, or write it with -o.
+
+Colors come from the current theme unless --theme or --color is given.
+--theme takes a theme.json, a palette.json or a saved theme file; roles a
+theme.json doesn't have are generated from its primary color. --color
+generates a scheme from a seed color with the scheme variant from
+config.json (or --scheme).
+
+Unknown roles, filters and unclosed expressions are all reported with
+their line numbers after the result, which keeps the expressions that
+failed as written, and the command exits with an error. Front matter of
+user templates is skipped.
+
+Examples:
+  hecate template render ~/.config/HecateShell/templates/foot.ini
+  hecate template render foot.ini --color "#7aa2f7" --mode light
+  hecate template render foot.ini --theme ~/backup/theme.json -o /tmp/foot.ini`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runTemplateRender,
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateRenderCmd)
+
+	templateRenderCmd.Flags().String("theme", "", "Render with the colors of a theme.json, palette.json or saved theme file")
+	templateRenderCmd.Flags().String("color", "", "Render with a scheme generated from a seed color")
+	templateRenderCmd.Flags().String("mode", "", "Mode the default variant resolves to: dark, light or auto (auto keeps a saved theme's mode)")
+	templateRenderCmd.Flags().String("scheme", "", "Material scheme variant for --color")
+	templateRenderCmd.Flags().StringP("output", "o", "", "Write the result to a file instead of printing it")
+	templateRenderCmd.MarkFlagsMutuallyExclusive("theme", "color")
+}
+
+func runTemplateRender(cmd *cobra.Command, args []string) error {
+	mode, _ := cmd.Flags().GetString("mode")
+	if mode != "" {
+		if err := theme.ValidateMode(mode); err != nil {
+			return err
+		}
+	}
+
+	src, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	palette, err := templatePalette(cmd, mode)
+	if err != nil {
+		return err
+	}
+	// Seed colors resolve auto themselves, and other palettes keep their own mode
+	if mode == theme.ModeDark || mode == theme.ModeLight {
+		palette.Mode = mode
+	}
+
+	data, err := palette.Data()
+	if err != nil {
+		return err
+	}
+
+	// The output is still shown when expressions fail, with those left as written
+	out, renderErr := templates.RenderTemplate(string(src), data)
+	if renderErr != nil && out == "" {
+		return fmt.Errorf("%s:\n%w", args[0], renderErr)
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		fmt.Print(out)
+	} else {
+		if err := os.WriteFile(output, []byte(out), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output, err)
+		}
+		fmt.Printf("Rendered %s to %s\n", args[0], output)
+	}
+
+	if renderErr != nil {
+		return fmt.Errorf("%s:\n%w", args[0], renderErr)
+	}
+	return nil
+}
+
+// templatePalette returns the colors to render with: a file, a seed color or the current theme
+func templatePalette(cmd *cobra.Command, mode string) (theme.Palette, error) {
+	themeFile, _ := cmd.Flags().GetString("theme")
+	color, _ := cmd.Flags().GetString("color")
+	switch {
+	case themeFile != "":
+		return theme.PaletteFromFile(themeFile)
+	case color != "":
+		settings, err := config.LoadThemeSettings()
+		if err != nil {
+			return theme.Palette{}, err
+		}
+		scheme := settings.Scheme
+		if s, _ := cmd.Flags().GetString("scheme"); s != "" {
+			scheme = s
+		}
+		variant, err := material.ParseVariant(scheme)
+		if err != nil {
+			return theme.Palette{}, err
+		}
+		if mode == "" {
+			mode = settings.Mode
+		}
+		return theme.PaletteFromColor(color, theme.Options{Mode: mode, Scheme: variant})
+	}
+	return theme.LoadPalette()
+}
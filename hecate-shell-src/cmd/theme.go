@@ -5,7 +5,9 @@ import (
 
 	"hecate-shell/internal/config"
 	"hecate-shell/internal/hooks"
+	"hecate-shell/internal/material"
 	"hecate-shell/internal/niri"
+	"hecate-shell/internal/theme"
 
 	"github.com/spf13/cobra"
 )
@@ -21,13 +23,101 @@ var themeReloadCmd = &cobra.Command{
 	Short: "Reload the current theme",
 	Long: `Reload theme by updating niri colors and running post-theme hooks.
 
-The shell will automatically hot-reload from theme.json.`,
+The shell will automatically hot-reload from theme.json.
+
+With --generate (implied by --mode and --scheme), colors are first
+regenerated from the current wallpaper using the mode and scheme saved
+in config.json.
+
+Examples:
+  hecate theme reload
+  hecate theme reload --generate
+  hecate theme reload --mode light
+  hecate theme reload --scheme vibrant`,
 	RunE: runThemeReload,
 }
 
+var themeSchemeCmd = &cobra.Command{
+	Use:   "scheme [name]",
+	Short: "Show or set the Material scheme variant",
+	Long: `Show or set the Material scheme variant used for theme generation.
+
+The choice is saved to config.json and used by every later regeneration.
+Setting a scheme regenerates the theme from the current wallpaper.
+
+Schemes:
+  tonal-spot, expressive, vibrant, monochrome, neutral,
+  rainbow, fruit-salad, content, fidelity (default)
+
+Examples:
+  hecate theme scheme
+  hecate theme scheme tonal-spot`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runThemeScheme,
+}
+
+var themeFromColorCmd = &cobra.Command{
+	Use:   "from-color <hex>",
+	Short: "Generate a theme from a seed color",
+	Long: `Generate a full Material You scheme from a single seed color.
+
+Writes theme.json and every template target, then updates niri colors
+and runs post-theme hooks. The wallpaper is left unchanged.
+
+Examples:
+  hecate theme from-color "#7aa2f7"
+  hecate theme from-color 7aa2f7 --mode light --scheme tonal-spot`,
+	Args: cobra.ExactArgs(1),
+	RunE: runThemeFromColor,
+}
+
+var themeImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a base16, pywal, Gogh or iTerm2 color scheme",
+	Long: `Import a community color scheme and render every template target from it.
+
+Supported formats:
+  base16/base24 YAML, pywal colors.json, Gogh YAML/JSON, iTerm2 .itermcolors
+
+Terminal colors are mapped onto the Material roles: blue becomes primary,
+magenta secondary, cyan tertiary and red error; surfaces are shaded from
+the scheme's background towards its foreground. The other mode is a
+Material scheme seeded from the blue; --mode light or dark renders it
+instead of the scheme's own.
+
+Examples:
+  hecate theme import ~/schemes/gruvbox-dark.yaml
+  hecate theme import ~/.cache/wal/colors.json
+  hecate theme import Dracula.itermcolors
+  hecate theme import ~/schemes/gruvbox-dark.yaml --mode light`,
+	Args: cobra.ExactArgs(1),
+	RunE: runThemeImport,
+}
+
 func init() {
 	rootCmd.AddCommand(themeCmd)
+
 	themeCmd.AddCommand(themeReloadCmd)
+	themeReloadCmd.Flags().BoolP("generate", "g", false, "Regenerate colors from the current wallpaper")
+	addThemeFlags(themeReloadCmd)
+
+	themeCmd.AddCommand(themeSchemeCmd)
+	// runThemeScheme passes its argument on as --scheme
+	addThemeFlags(themeSchemeCmd)
+	themeSchemeCmd.Flags().MarkHidden("scheme")
+
+	themeCmd.AddCommand(themeFromColorCmd)
+	addThemeFlags(themeFromColorCmd)
+
+	themeCmd.AddCommand(themeImportCmd)
+	themeImportCmd.Flags().String("mode", "", "Mode to render the scheme in: dark, light or auto (from its background)")
+	themeImportCmd.Flags().String("scheme", "", "Material scheme variant for the mode the scheme doesn't cover")
+}
+
+// addThemeFlags adds --mode and --scheme to a command that generates a theme
+func addThemeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("mode", "", "Color scheme mode: dark, light or auto (saved to config.json)")
+	cmd.Flags().String("scheme", "", "Material scheme variant, e.g. tonal-spot (saved to config.json)")
 }
 
 func runThemeReload(cmd *cobra.Command, args []string) error {
@@ -36,8 +126,197 @@ func runThemeReload(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("HecateShell is not installed. Run 'hecate install' first")
 	}
 
+	generate, _ := cmd.Flags().GetBool("generate")
+
+	opts, changed, err := themeOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	if generate || changed {
+		wallpaperPath, err := config.GetWallpaperPath()
+		if err != nil {
+			return err
+		}
+
+		recordThemeHistory()
+		hooks.RunUserHooks(hooks.PreTheme)
+		fmt.Printf("Regenerating theme from %s...\n", wallpaperPath)
+		if err := theme.GenerateFromImage(wallpaperPath, opts); err != nil {
+			return fmt.Errorf("failed to generate theme: %w", err)
+		}
+		recordThemeHistory()
+	}
+
 	fmt.Println("Reloading theme...")
+	applyGeneratedTheme()
+
+	return nil
+}
+
+func runThemeScheme(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		settings, err := config.LoadThemeSettings()
+		if err != nil {
+			return err
+		}
+
+		for _, v := range material.Variants {
+			marker := "  "
+			if string(v) == settings.Scheme {
+				marker = "* "
+			}
+			fmt.Printf("%s%s\n", marker, v)
+		}
+		return nil
+	}
+
+	if !config.IsInstalled() {
+		return fmt.Errorf("HecateShell is not installed. Run 'hecate install' first")
+	}
+
+	if err := cmd.Flags().Set("scheme", args[0]); err != nil {
+		return err
+	}
+
+	opts, _, err := themeOptions(cmd)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Scheme set to %s\n", opts.Scheme)
+
+	wallpaperPath, err := config.GetWallpaperPath()
+	if err != nil {
+		fmt.Println("No wallpaper set; the scheme will be used on the next generation.")
+		return nil
+	}
+
+	recordThemeHistory()
+	hooks.RunUserHooks(hooks.PreTheme)
+	fmt.Printf("Regenerating theme from %s...\n", wallpaperPath)
+	if err := theme.GenerateFromImage(wallpaperPath, opts); err != nil {
+		return fmt.Errorf("failed to generate theme: %w", err)
+	}
+	recordThemeHistory()
 
+	applyGeneratedTheme()
+	return nil
+}
+
+func runThemeFromColor(cmd *cobra.Command, args []string) error {
+	if !config.IsInstalled() {
+		return fmt.Errorf("HecateShell is not installed. Run 'hecate install' first")
+	}
+
+	opts, _, err := themeOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	recordThemeHistory()
+	hooks.RunUserHooks(hooks.PreTheme)
+	fmt.Printf("Generating theme from %s...\n", args[0])
+	if err := theme.GenerateFromColor(args[0], opts); err != nil {
+		return fmt.Errorf("failed to generate theme: %w", err)
+	}
+	recordThemeHistory()
+
+	applyGeneratedTheme()
+	return nil
+}
+
+func runThemeImport(cmd *cobra.Command, args []string) error {
+	if !config.IsInstalled() {
+		return fmt.Errorf("HecateShell is not installed. Run 'hecate install' first")
+	}
+
+	opts, err := overrideOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	recordThemeHistory()
+	hooks.RunUserHooks(hooks.PreTheme)
+	if _, err := theme.Import(args[0], opts); err != nil {
+		return fmt.Errorf("failed to import scheme: %w", err)
+	}
+	recordThemeHistory()
+
+	applyGeneratedTheme()
+	return nil
+}
+
+// themeOptions loads the theme settings from config.json and applies any
+// --mode/--scheme overrides, saving them back so later regenerations reuse them.
+// Reports whether a flag changed the saved settings.
+func themeOptions(cmd *cobra.Command) (theme.Options, bool, error) {
+	opts, settings, changed, err := flagThemeOptions(cmd)
+	if err != nil {
+		return theme.Options{}, false, err
+	}
+
+	if changed {
+		if err := config.SaveThemeSettings(settings); err != nil {
+			return theme.Options{}, false, err
+		}
+	}
+	return opts, changed, nil
+}
+
+// overrideOptions returns only the --mode and --scheme given on the command line, for
+// commands that apply existing colors instead of generating them from config.json
+func overrideOptions(cmd *cobra.Command) (theme.Options, error) {
+	var opts theme.Options
+	if mode, _ := cmd.Flags().GetString("mode"); mode != "" {
+		if err := theme.ValidateMode(mode); err != nil {
+			return opts, err
+		}
+		opts.Mode = mode
+	}
+	if scheme, _ := cmd.Flags().GetString("scheme"); scheme != "" {
+		variant, err := material.ParseVariant(scheme)
+		if err != nil {
+			return opts, err
+		}
+		opts.Scheme = variant
+	}
+	return opts, nil
+}
+
+// flagThemeOptions is themeOptions without saving, for previews that must not touch config.json
+func flagThemeOptions(cmd *cobra.Command) (theme.Options, config.ThemeSettings, bool, error) {
+	settings, err := config.LoadThemeSettings()
+	if err != nil {
+		return theme.Options{}, settings, false, err
+	}
+
+	changed := false
+	if mode, _ := cmd.Flags().GetString("mode"); mode != "" {
+		if err := theme.ValidateMode(mode); err != nil {
+			return theme.Options{}, settings, false, err
+		}
+		settings.Mode = mode
+		changed = true
+	}
+	if scheme, _ := cmd.Flags().GetString("scheme"); scheme != "" {
+		variant, err := material.ParseVariant(scheme)
+		if err != nil {
+			return theme.Options{}, settings, false, err
+		}
+		settings.Scheme = string(variant)
+		changed = true
+	}
+
+	variant, err := material.ParseVariant(settings.Scheme)
+	if err != nil {
+		return theme.Options{}, settings, false, fmt.Errorf("invalid theme.scheme in config.json: %w", err)
+	}
+
+	return theme.Options{Backend: settings.Backend, Mode: settings.Mode, Scheme: variant}, settings, changed, nil
+}
+
+// applyGeneratedTheme pushes a freshly written theme.json to niri and runs post-theme hooks
+func applyGeneratedTheme() {
 	// Update niri config colors
 	if err := niri.UpdateNiriColors(); err != nil {
 		fmt.Printf("Warning: failed to update niri colors: %v\n", err)
@@ -45,8 +324,14 @@ func runThemeReload(cmd *cobra.Command, args []string) error {
 		fmt.Println("Niri colors updated!")
 	}
 
-	// Run post-theme hooks (pywalfox, etc.)
+	// Recolor terminals, reload applications and run user post-theme hooks
 	hooks.RunPostThemeHooks()
+}
 
-	return nil
+// runWallpaperHooksIfChanged runs the post-wallpaper hooks if restoring a theme brought
+// back a different wallpaper than the previous one
+func runWallpaperHooksIfChanged(previous string) {
+	if wallpaper, err := config.GetWallpaperPath(); err == nil && wallpaper != previous {
+		hooks.RunUserHooks(hooks.PostWallpaper)
+	}
 }
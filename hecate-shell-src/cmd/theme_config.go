@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"hecate-shell/internal/matugen"
+
+	"github.com/spf13/cobra"
+)
+
+var themeConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the merged matugen config",
+	Long: `Inspect the config passed to matugen when the matugen backend is used.
+
+Your ~/.config/matugen/config.toml is parsed and HecateShell's success,
+warning and info colors are declared in it as custom colors. matugen
+renders your own templates; HecateShell renders its targets from the
+colors matugen generates, and --print lists them as comments at the end.
+A template with the same name and output as a HecateShell target is
+replaced by it, and templates writing a target's output are reported.
+
+Examples:
+  hecate theme config --print`,
+	Args: cobra.NoArgs,
+	RunE: runThemeConfig,
+}
+
+func init() {
+	themeCmd.AddCommand(themeConfigCmd)
+	themeConfigCmd.Flags().Bool("print", false, "Print the effective merged config")
+}
+
+func runThemeConfig(cmd *cobra.Command, args []string) error {
+	merged, collisions, err := matugen.MergedConfig()
+	if err != nil {
+		return err
+	}
+
+	// Collisions go to stderr so --print output stays valid TOML
+	for _, collision := range collisions {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", collision)
+	}
+
+	if printConfig, _ := cmd.Flags().GetBool("print"); printConfig {
+		fmt.Print(merged)
+		return nil
+	}
+
+	if len(collisions) == 0 {
+		fmt.Println("No collisions between your matugen config and HecateShell's templates.")
+	}
+	fmt.Println("Run 'hecate theme config --print' to show the merged config.")
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/hooks"
+	"hecate-shell/internal/theme"
+
+	"github.com/spf13/cobra"
+)
+
+var themeUndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Go back to the previous theme",
+	Long: `Restore the theme and wallpaper that were live before the last change.
+
+Every template target is re-rendered from the recorded palette.
+
+Examples:
+  hecate theme undo
+  hecate theme redo`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runThemeStep(theme.CanUndo, theme.Undo)
+	},
+}
+
+var themeRedoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Re-apply the last undone theme",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runThemeStep(theme.CanRedo, theme.Redo)
+	},
+}
+
+var themeHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent themes",
+	Args:  cobra.NoArgs,
+	RunE:  runThemeHistory,
+}
+
+func init() {
+	themeCmd.AddCommand(themeUndoCmd)
+	themeCmd.AddCommand(themeRedoCmd)
+	themeCmd.AddCommand(themeHistoryCmd)
+}
+
+func runThemeStep(check func() error, step func() (theme.Snapshot, error)) error {
+	if !config.IsInstalled() {
+		return fmt.Errorf("HecateShell is not installed. Run 'hecate install' first")
+	}
+
+	// Capture hand edits made since the last recorded theme so they can be redone
+	recordThemeHistory()
+	// Pre-theme hooks only run if the theme is going to change
+	if err := check(); err != nil {
+		return err
+	}
+	hooks.RunUserHooks(hooks.PreTheme)
+	wallpaper, _ := config.GetWallpaperPath()
+
+	snapshot, err := step()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored theme from %s\n", snapshot.Created.Format("2006-01-02 15:04:05"))
+	applyGeneratedTheme()
+	runWallpaperHooksIfChanged(wallpaper)
+	return nil
+}
+
+func runThemeHistory(cmd *cobra.Command, args []string) error {
+	entries, position, err := theme.History()
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No theme history yet.")
+		return nil
+	}
+
+	for i, s := range entries {
+		marker := "  "
+		if i == position {
+			marker = "* "
+		}
+		fmt.Printf("%s%s  %-5s %-12s %s\n", marker, s.Created.Format("2006-01-02 15:04:05"), s.Palette.Mode, s.Palette.Scheme, s.Palette.Label())
+	}
+	return nil
+}
+
+// recordThemeHistory adds the live theme to the undo history.
+// Themes without a palette (matugen) can't be re-applied and are skipped.
+func recordThemeHistory() {
+	if err := theme.RecordHistory(); err != nil && !errors.Is(err, theme.ErrNoPalette) {
+		fmt.Printf("Warning: theme not recorded in history: %v\n", err)
+	}
+}
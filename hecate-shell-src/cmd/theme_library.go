@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/hooks"
+	"hecate-shell/internal/theme"
+
+	"github.com/spf13/cobra"
+)
+
+var themeSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save the current theme under a name",
+	Long: `Save the current theme to the theme library.
+
+The snapshot holds theme.json, the full color palette, the wallpaper
+path and the scheme settings. Saving over an existing name replaces it.
+
+Examples:
+  hecate theme save lain`,
+	Args: cobra.ExactArgs(1),
+	RunE: runThemeSave,
+}
+
+var themeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved themes",
+	Args:  cobra.NoArgs,
+	RunE:  runThemeList,
+}
+
+var themeApplyCmd = &cobra.Command{
+	Use:   "apply <name>",
+	Short: "Apply a saved theme",
+	Long: `Apply a saved theme without re-extracting colors.
+
+Restores the theme's wallpaper and scheme settings, re-renders every
+template target from the saved palette, then updates niri colors and
+runs post-theme hooks. --mode applies the palette's other mode instead.
+
+Examples:
+  hecate theme apply lain
+  hecate theme apply lain --mode light`,
+	Args: cobra.ExactArgs(1),
+	RunE: runThemeApply,
+}
+
+var themeRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Delete a saved theme",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runThemeRm,
+}
+
+func init() {
+	themeCmd.AddCommand(themeSaveCmd)
+	themeCmd.AddCommand(themeListCmd)
+	themeCmd.AddCommand(themeApplyCmd)
+	themeApplyCmd.Flags().String("mode", "", "Mode to apply the theme in: dark, light or auto (saved to config.json)")
+	themeApplyCmd.Flags().String("scheme", "", "Material scheme variant for later regenerations (saved to config.json)")
+	themeCmd.AddCommand(themeRmCmd)
+}
+
+func runThemeSave(cmd *cobra.Command, args []string) error {
+	if !config.IsInstalled() {
+		return fmt.Errorf("HecateShell is not installed. Run 'hecate install' first")
+	}
+
+	if _, err := theme.SaveSnapshot(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("Theme saved as %s\n", args[0])
+	return nil
+}
+
+func runThemeList(cmd *cobra.Command, args []string) error {
+	snapshots, err := theme.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Println("No saved themes. Save the current one with 'hecate theme save <name>'.")
+		return nil
+	}
+
+	for _, s := range snapshots {
+		fmt.Printf("%-20s %-5s %-12s %s\n", s.Name, s.Palette.Mode, s.Palette.Scheme, s.Palette.Label())
+	}
+	return nil
+}
+
+func runThemeApply(cmd *cobra.Command, args []string) error {
+	if !config.IsInstalled() {
+		return fmt.Errorf("HecateShell is not installed. Run 'hecate install' first")
+	}
+
+	opts, err := overrideOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return applySavedTheme(args[0], opts)
+}
+
+// applySavedTheme applies a saved theme, keeping the outgoing one in history. The mode
+// and scheme set in opts replace the ones it was saved with.
+func applySavedTheme(name string, opts theme.Options) error {
+	snapshot, err := theme.LoadSnapshot(name)
+	if err != nil {
+		return err
+	}
+	snapshot = snapshot.WithOptions(opts)
+
+	recordThemeHistory()
+	hooks.RunUserHooks(hooks.PreTheme)
+	wallpaper, _ := config.GetWallpaperPath()
+	fmt.Printf("Applying theme %s...\n", snapshot.Name)
+	if err := theme.ApplySnapshot(snapshot); err != nil {
+		return fmt.Errorf("failed to apply theme: %w", err)
+	}
+	recordThemeHistory()
+
+	applyGeneratedTheme()
+	runWallpaperHooksIfChanged(wallpaper)
+	return nil
+}
+
+func runThemeRm(cmd *cobra.Command, args []string) error {
+	if err := theme.RemoveSnapshot(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed theme %s\n", args[0])
+	return nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"hecate-shell/internal/hooks"
+	"hecate-shell/internal/theme"
+
+	"github.com/spf13/cobra"
+)
+
+var themeLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check theme colors for WCAG contrast",
+	Long: `Check the contrast of every foreground/background pair in theme.json and
+the generated kitty and alacritty palettes against WCAG.
+
+Body text needs 4.5:1 for AA and 7:1 for AAA; accents, outlines and
+dimmed terminal text need 3:1 and 4.5:1.
+
+With --fix, failing foreground colors are nudged lighter or darker (hue
+and chroma are kept) until they pass, and every target is re-rendered.
+Surface colors are never changed.
+
+Examples:
+  hecate theme lint
+  hecate theme lint --level aaa
+  hecate theme lint --fix`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runThemeLint,
+}
+
+func init() {
+	themeCmd.AddCommand(themeLintCmd)
+	themeLintCmd.Flags().String("level", theme.LevelAA, "WCAG level to check: aa or aaa")
+	themeLintCmd.Flags().Bool("fix", false, "Nudge failing colors until they pass")
+	themeLintCmd.Flags().BoolP("verbose", "v", false, "Show passing pairs too")
+}
+
+func runThemeLint(cmd *cobra.Command, args []string) error {
+	level, _ := cmd.Flags().GetString("level")
+	fix, _ := cmd.Flags().GetBool("fix")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	level = strings.ToLower(level)
+	if err := theme.ValidateLevel(level); err != nil {
+		return err
+	}
+
+	if fix {
+		recordThemeHistory()
+		hooks.RunUserHooks(hooks.PreTheme)
+		roles, err := theme.FixContrast(level)
+		if err != nil {
+			return fmt.Errorf("failed to fix contrast: %w", err)
+		}
+		if len(roles) == 0 {
+			fmt.Println("Nothing to fix.")
+		} else {
+			fmt.Printf("Adjusted %s\n", strings.Join(roles, ", "))
+			recordThemeHistory()
+			applyGeneratedTheme()
+		}
+		fmt.Println()
+	}
+
+	checks, err := theme.Lint()
+	if err != nil {
+		return err
+	}
+	if len(checks) == 0 {
+		return fmt.Errorf("no generated theme files to check")
+	}
+
+	failures := 0
+	for _, check := range checks {
+		passes := check.Passes(level)
+		if !passes {
+			failures++
+		}
+		if passes && !verbose {
+			continue
+		}
+
+		status := "FAIL"
+		if passes {
+			status = "ok  "
+		}
+		pair := fmt.Sprintf("%s on %s", check.Foreground, check.Background)
+		fmt.Printf("%s  %-17s %-52s %5.2f  (needs %.1f)\n", status, check.Target, pair, check.Ratio, check.Required(level))
+	}
+
+	levelName := strings.ToUpper(level)
+	if failures > 0 {
+		return fmt.Errorf("%d of %d pairs fail WCAG %s", failures, len(checks), levelName)
+	}
+	fmt.Printf("All %d pairs pass WCAG %s\n", len(checks), levelName)
+	return nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/picker"
+	"hecate-shell/internal/theme"
+
+	"github.com/spf13/cobra"
+)
+
+var themePickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Browse wallpapers and saved themes interactively",
+	Long: `Browse the wallpapers in ~/.config/HecateShell/wallpapers and your saved
+themes with a live preview of the colors each one would produce.
+
+Keys:
+  ↑/↓, j/k    browse
+  tab, m      switch between dark, light and auto
+  ←/→, [/]    switch scheme variant
+  enter       apply
+  q, esc      quit
+
+A wallpaper is applied like 'hecate wallpaper <path> -g', and the mode and
+scheme it was previewed with are saved to config.json. Saved themes are
+applied as they were saved.
+
+Examples:
+  hecate theme pick
+  hecate theme pick --scheme vibrant`,
+	Args: cobra.NoArgs,
+	RunE: runThemePick,
+}
+
+func init() {
+	themeCmd.AddCommand(themePickCmd)
+	addThemeFlags(themePickCmd)
+}
+
+func runThemePick(cmd *cobra.Command, args []string) error {
+	if !config.IsInstalled() {
+		return fmt.Errorf("HecateShell is not installed. Run 'hecate install' first")
+	}
+
+	// --mode and --scheme only set where the picker starts
+	_, settings, _, err := flagThemeOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	choice, ok, err := picker.Run(settings)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	if choice.Kind == picker.KindTheme {
+		return applySavedTheme(choice.Name, theme.Options{})
+	}
+
+	settings.Mode = choice.Mode
+	settings.Scheme = string(choice.Scheme)
+	if err := config.SaveThemeSettings(settings); err != nil {
+		return err
+	}
+
+	opts := theme.Options{Backend: settings.Backend, Mode: settings.Mode, Scheme: choice.Scheme}
+	return setWallpaper(choice.Path, "", 0, true, opts)
+}
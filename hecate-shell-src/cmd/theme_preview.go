@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"hecate-shell/internal/material"
+	"hecate-shell/internal/theme"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var themePreviewCmd = &cobra.Command{
+	Use:   "preview [name|theme.json]",
+	Short: "Show theme colors in the terminal",
+	Long: `Render truecolor swatches for every role in theme.json plus the 16
+terminal colors from the kitty template.
+
+Without an argument the current theme is shown. Pass the name of a saved
+theme or the path to a theme.json file to preview something else.
+
+Examples:
+  hecate theme preview
+  hecate theme preview lain
+  hecate theme preview ~/backup/theme.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runThemePreview,
+}
+
+func init() {
+	themeCmd.AddCommand(themePreviewCmd)
+}
+
+func runThemePreview(cmd *cobra.Command, args []string) error {
+	var preview theme.Preview
+	var err error
+
+	switch {
+	case len(args) == 0:
+		preview, err = theme.PreviewLive()
+	case isThemeFile(args[0]):
+		preview, err = theme.PreviewFile(args[0])
+	default:
+		preview, err = theme.PreviewSnapshot(args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	printPreview(preview)
+	return nil
+}
+
+// isThemeFile reports whether a preview argument is a path rather than a saved theme name
+func isThemeFile(arg string) bool {
+	if strings.HasSuffix(arg, ".json") || strings.Contains(arg, "/") {
+		return true
+	}
+	_, err := os.Stat(arg)
+	return err == nil
+}
+
+// printPreview renders a theme preview as truecolor swatches
+func printPreview(preview theme.Preview) {
+	title := lipgloss.NewStyle().Bold(true)
+	dim := lipgloss.NewStyle().Faint(true)
+
+	fmt.Println(title.Render(preview.Title))
+	fmt.Println()
+
+	colors := make(map[string]uint32, len(preview.Roles))
+	width := 0
+	for _, role := range preview.Roles {
+		colors[role.Name] = role.Color
+		width = max(width, len(role.Name))
+	}
+
+	for _, role := range preview.Roles {
+		hex := material.HexFromArgb(role.Color)
+		swatch := lipgloss.NewStyle().Background(lipgloss.Color(hex)).Render("      ")
+
+		// Show roles with a matching "<role>Text" as they are used: text on the color
+		sample := "    "
+		if text, ok := colors[role.Name+"Text"]; ok {
+			sample = lipgloss.NewStyle().
+				Background(lipgloss.Color(hex)).
+				Foreground(lipgloss.Color(material.HexFromArgb(text))).
+				Render(" Aa ")
+		}
+
+		fmt.Printf("  %s%s  %-*s %s\n", swatch, sample, width, role.Name, dim.Render(hex))
+	}
+
+	if len(preview.ANSI) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(title.Render("Terminal"))
+	for row := 0; row < 2; row++ {
+		var cells []string
+		for i := row * 8; i < row*8+8; i++ {
+			color := preview.ANSI[i]
+			label := lipgloss.Color("#000000")
+			if material.ContrastRatio(color, 0xffffffff) > material.ContrastRatio(color, 0xff000000) {
+				label = lipgloss.Color("#ffffff")
+			}
+			cells = append(cells, lipgloss.NewStyle().
+				Background(lipgloss.Color(material.HexFromArgb(color))).
+				Foreground(label).
+				Width(5).
+				Align(lipgloss.Center).
+				Render(fmt.Sprint(i)))
+		}
+		fmt.Println("  " + strings.Join(cells, ""))
+	}
+}
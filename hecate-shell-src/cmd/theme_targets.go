@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"hecate-shell/internal/templates"
+
+	"github.com/spf13/cobra"
+)
+
+var themeTargetsCmd = &cobra.Command{
+	Use:   "targets",
+	Short: "Manage template targets",
+	Long: `Manage the template targets rendered on every theme generation.
+
+Targets are declared in config/templates.json. Add your own by dropping
+manifests with the same layout into ~/.config/HecateShell/templates.d/;
+entries there replace shipped targets of the same name.
+
+Files in ~/.config/HecateShell/templates/ replace the shipped template of
+the same name in config/templates/. Other files there become new targets
+when they start with front matter declaring an output:
+
+  ---
+  output: ~/.config/foot/colors.ini
+  post_hook: pkill -USR1 foot
+  ---
+
+Targets that overwrite files you may have customized (the Vencord theme)
+are disabled until you enable them.
+
+Examples:
+  hecate theme targets list
+  hecate theme targets enable hecate_discord
+  hecate theme targets disable hecate_gtk3`,
+}
+
+var themeTargetsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List template targets",
+	Args:  cobra.NoArgs,
+	RunE:  runThemeTargetsList,
+}
+
+var themeTargetsEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Enable a template target",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTargetEnabled(args[0], true)
+	},
+}
+
+var themeTargetsDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Disable a template target",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTargetEnabled(args[0], false)
+	},
+}
+
+func init() {
+	themeCmd.AddCommand(themeTargetsCmd)
+	themeTargetsCmd.AddCommand(themeTargetsListCmd)
+	themeTargetsCmd.AddCommand(themeTargetsEnableCmd)
+	themeTargetsCmd.AddCommand(themeTargetsDisableCmd)
+}
+
+func runThemeTargetsList(cmd *cobra.Command, args []string) error {
+	targets, err := templates.Manifest()
+	if err != nil {
+		return err
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	for _, target := range targets {
+		status := "off"
+		if target.Enabled {
+			status = "on "
+		}
+		output := target.Output
+		if homeDir != "" {
+			if rest, ok := strings.CutPrefix(output, homeDir+"/"); ok {
+				output = "~/" + rest
+			}
+		}
+		if target.User {
+			output += " (user template)"
+		}
+		fmt.Printf("%s  %-20s %s\n", status, target.Name, output)
+	}
+	return nil
+}
+
+func setTargetEnabled(name string, enabled bool) error {
+	if err := templates.SetEnabled(name, enabled); err != nil {
+		return err
+	}
+
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+	fmt.Printf("Target %s %s. Run 'hecate theme reload -g' to regenerate.\n", name, state)
+	return nil
+}
@@ -1,14 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"hecate-shell/internal/config"
 	"hecate-shell/internal/hooks"
-	"hecate-shell/internal/matugen"
-	"hecate-shell/internal/niri"
+	"hecate-shell/internal/theme"
 
 	"github.com/spf13/cobra"
 )
@@ -21,6 +20,9 @@ var wallpaperCmd = &cobra.Command{
 Examples:
   hecate wallpaper /wallpaper.jpg
   hecate wallpaper /wallpaper.jpg --generate-theme
+  hecate wallpaper /wallpaper.jpg -g --mode auto
+  hecate wallpaper /wallpaper.jpg -g --scheme tonal-spot
+  hecate wallpaper /wallpaper.jpg --preview --scheme vibrant
   hecate wallpaper /wallpaper.jpg --transition fade --duration 2`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWallpaper,
@@ -31,6 +33,8 @@ func init() {
 	wallpaperCmd.Flags().BoolP("generate-theme", "g", false, "Generate theme colors from wallpaper")
 	wallpaperCmd.Flags().StringP("transition", "t", "", "Transition effect (only 'fade' is supported currently)")
 	wallpaperCmd.Flags().IntP("duration", "d", 0, "Transition duration in milliseconds")
+	addThemeFlags(wallpaperCmd)
+	wallpaperCmd.Flags().Bool("preview", false, "Preview the generated colors without changing anything")
 }
 
 func runWallpaper(cmd *cobra.Command, args []string) error {
@@ -39,6 +43,24 @@ func runWallpaper(cmd *cobra.Command, args []string) error {
 	transition, _ := cmd.Flags().GetString("transition")
 	duration, _ := cmd.Flags().GetInt("duration")
 
+	if preview, _ := cmd.Flags().GetBool("preview"); preview {
+		return previewWallpaper(cmd, wallpaperPath)
+	}
+
+	// Resolve theme settings up front so a bad --mode/--scheme fails before anything is
+	// written. They are only saved when a theme is generated with them.
+	opts, settings, changed, err := flagThemeOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if changed {
+		if !generateTheme {
+			fmt.Println("Warning: --mode and --scheme only apply with --generate-theme")
+		} else if err := config.SaveThemeSettings(settings); err != nil {
+			return err
+		}
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return fmt.Errorf("failed to get home directory: %w", err)
@@ -50,26 +72,25 @@ func runWallpaper(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Get config path
-	configPath := filepath.Join(homeDir, ".config", "HecateShell", "config.json")
+	return setWallpaper(absPath, transition, duration, generateTheme, opts)
+}
+
+// setWallpaper saves the wallpaper to config.json and optionally generates a theme from it
+func setWallpaper(absPath, transition string, duration int, generateTheme bool, opts theme.Options) error {
+	// Keep the outgoing theme so it can be undone
+	recordThemeHistory()
 
 	// Read existing config
-	configData, err := os.ReadFile(configPath)
+	userConfig, err := config.LoadUserConfig()
 	if err != nil {
-		return fmt.Errorf("failed to read config.json: %w", err)
-	}
-
-	// Parse config
-	var config map[string]interface{}
-	if err := json.Unmarshal(configData, &config); err != nil {
-		return fmt.Errorf("failed to parse config.json: %w", err)
+		return err
 	}
 
 	// Update wallpaper section
-	wallpaperConfig, ok := config["wallpaper"].(map[string]interface{})
+	wallpaperConfig, ok := userConfig["wallpaper"].(map[string]interface{})
 	if !ok {
 		wallpaperConfig = make(map[string]interface{})
-		config["wallpaper"] = wallpaperConfig
+		userConfig["wallpaper"] = wallpaperConfig
 	}
 
 	wallpaperConfig["path"] = absPath
@@ -81,13 +102,8 @@ func runWallpaper(cmd *cobra.Command, args []string) error {
 	}
 
 	// Write updated config
-	updatedData, err := json.MarshalIndent(config, "", "    ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	if err := os.WriteFile(configPath, updatedData, 0644); err != nil {
-		return fmt.Errorf("failed to write config.json: %w", err)
+	if err := config.SaveUserConfig(userConfig); err != nil {
+		return err
 	}
 
 	fmt.Printf("Wallpaper set: %s\n", absPath)
@@ -95,29 +111,51 @@ func runWallpaper(cmd *cobra.Command, args []string) error {
 	// Generate theme if flag is set
 	if generateTheme {
 		fmt.Println("\nGenerating theme from wallpaper colors...")
+		hooks.RunUserHooks(hooks.PreTheme)
 
-		// Run matugen with merged config (user's config + HecateShell template)
-		if err := matugen.RunMatugen("image", absPath); err != nil {
-			return fmt.Errorf("failed to generate theme with matugen: %w", err)
+		// Extract colors and render every template (built-in engine or matugen)
+		if err := theme.GenerateFromImage(absPath, opts); err != nil {
+			return fmt.Errorf("failed to generate theme: %w", err)
 		}
 
-		// Update niri config colors
-		if err := niri.UpdateNiriColors(); err != nil {
-			fmt.Printf("Warning: failed to update niri colors: %v\n", err)
-		} else {
-			fmt.Println("Niri colors updated!")
-		}
-
-		// Run post-theme hooks (pywalfox, etc.)
-		hooks.RunPostThemeHooks()
+		applyGeneratedTheme()
 
 		fmt.Println("Theme generated! Shell will auto-update within 1 second.")
 	}
 
+	recordThemeHistory()
+	hooks.RunUserHooks(hooks.PostWallpaper)
+
 	fmt.Println("Wallpaper will update within 1 second (hot-reload).")
 	return nil
 }
 
+// previewWallpaper shows the scheme a wallpaper would produce without touching the live config
+func previewWallpaper(cmd *cobra.Command, wallpaperPath string) error {
+	opts, _, _, err := flagThemeOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	absPath, err := resolveWallpaperPath(wallpaperPath, homeDir)
+	if err != nil {
+		return err
+	}
+
+	preview, err := theme.PreviewImage(absPath, opts)
+	if err != nil {
+		return fmt.Errorf("failed to preview theme: %w", err)
+	}
+
+	printPreview(preview)
+	return nil
+}
+
 // resolveWallpaperPath resolves wallpaper path from shortcut name or file path
 func resolveWallpaperPath(input, homeDir string) (string, error) {
 	// If path contains "/" or starts with "~" or "./", treat as file path
@@ -3,10 +3,12 @@ module hecate-shell
 go 1.23
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/charmbracelet/bubbletea v1.2.4
 	github.com/charmbracelet/harmonica v0.2.0
 	github.com/charmbracelet/lipgloss v1.0.0
 	github.com/spf13/cobra v1.10.2
+	golang.org/x/image v0.18.0
 )
 
 require (
@@ -0,0 +1,312 @@
+package colorscheme
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"hecate-shell/internal/material"
+)
+
+// Supported scheme formats
+const (
+	FormatBase16 = "base16"
+	FormatBase24 = "base24"
+	FormatPywal  = "pywal"
+	FormatGogh   = "gogh"
+	FormatITerm2 = "iterm2"
+)
+
+// Scheme is a terminal-style color scheme, normalized from any supported format
+type Scheme struct {
+	Name       string
+	Format     string
+	Background uint32
+	Foreground uint32
+	Cursor     uint32
+	ANSI       [16]uint32 // Black, red, green, yellow, blue, magenta, cyan, white, then the bright variants
+
+	// Surfaces lists extra background shades from darkest to lightest contrast
+	// with Background, when the format has them (base16's base01 and base02)
+	Surfaces []uint32
+}
+
+// Load reads a base16/base24 YAML, pywal colors.json, Gogh (YAML or JSON) or iTerm2 scheme
+func Load(path string) (Scheme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scheme{}, fmt.Errorf("failed to read scheme: %w", err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	scheme, err := Parse(data)
+	if err != nil {
+		return Scheme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if scheme.Name == "" {
+		scheme.Name = name
+	}
+	return scheme, nil
+}
+
+// Parse detects the format of a scheme and decodes it
+func Parse(data []byte) (Scheme, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return parseITerm2(data)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return parseJSON(data)
+	}
+	return parseYAML(data)
+}
+
+// parseJSON handles pywal's colors.json and Gogh's JSON themes
+func parseJSON(data []byte) (Scheme, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Scheme{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	// pywal nests its colors under "special" and "colors"
+	if colors, ok := raw["colors"].(map[string]interface{}); ok {
+		values := make(map[string]string)
+		for key, value := range colors {
+			if s, ok := value.(string); ok {
+				values[key] = s
+			}
+		}
+		if special, ok := raw["special"].(map[string]interface{}); ok {
+			for key, value := range special {
+				if s, ok := value.(string); ok {
+					values[key] = s
+				}
+			}
+		}
+		return fromPywal(values)
+	}
+
+	values := make(map[string]string)
+	for key, value := range raw {
+		if s, ok := value.(string); ok {
+			values[strings.ToLower(key)] = s
+		}
+	}
+	return fromKeys(values)
+}
+
+// yamlLineRe matches flat or nested "key: value" lines, which is all base16 and Gogh need
+var yamlLineRe = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+)\s*:\s*(.*?)\s*$`)
+
+// parseYAML handles base16/base24 and Gogh YAML schemes
+func parseYAML(data []byte) (Scheme, error) {
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		match := yamlLineRe.FindStringSubmatch(line)
+		if match == nil || match[2] == "" {
+			continue
+		}
+		values[strings.ToLower(match[1])] = strings.Trim(match[2], `"'`)
+	}
+	return fromKeys(values)
+}
+
+// fromKeys builds a scheme from flat base16/base24 or Gogh keys
+func fromKeys(values map[string]string) (Scheme, error) {
+	switch {
+	case values["base00"] != "":
+		return fromBase16(values)
+	case values["color_01"] != "":
+		return fromGogh(values)
+	}
+	return Scheme{}, fmt.Errorf("unrecognized scheme format (expected base16/base24, pywal, Gogh or iTerm2)")
+}
+
+func fromBase16(values map[string]string) (Scheme, error) {
+	base := make(map[string]uint32)
+	for i := 0; i < 24; i++ {
+		key := fmt.Sprintf("base%02X", i)
+		value, ok := values[strings.ToLower(key)]
+		if !ok {
+			if i < 16 {
+				return Scheme{}, fmt.Errorf("missing %s", key)
+			}
+			continue
+		}
+		argb, err := material.ArgbFromHex(value)
+		if err != nil {
+			return Scheme{}, fmt.Errorf("%s: %w", key, err)
+		}
+		base[key] = argb
+	}
+
+	scheme := Scheme{
+		Name:       firstOf(values, "scheme", "name"),
+		Format:     FormatBase16,
+		Background: base["base00"],
+		Foreground: base["base05"],
+		Cursor:     base["base05"],
+		Surfaces:   []uint32{base["base01"], base["base02"]},
+	}
+
+	// Standard base16 terminal mapping
+	normal := []string{"base00", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base05"}
+	bright := []string{"base03", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base07"}
+	if _, ok := base["base17"]; ok {
+		// base24 adds dedicated bright colors
+		scheme.Format = FormatBase24
+		bright = []string{"base03", "base12", "base14", "base13", "base16", "base17", "base15", "base07"}
+	}
+	for i := range normal {
+		scheme.ANSI[i] = base[normal[i]]
+		scheme.ANSI[i+8] = base[bright[i]]
+	}
+	return scheme, nil
+}
+
+func fromGogh(values map[string]string) (Scheme, error) {
+	scheme := Scheme{Name: values["name"], Format: FormatGogh}
+	for i := range scheme.ANSI {
+		key := fmt.Sprintf("color_%02d", i+1)
+		argb, err := parseColor(values, key)
+		if err != nil {
+			return Scheme{}, err
+		}
+		scheme.ANSI[i] = argb
+	}
+	return scheme, scheme.fillSpecial(values, "background", "foreground", "cursor")
+}
+
+func fromPywal(values map[string]string) (Scheme, error) {
+	scheme := Scheme{Format: FormatPywal}
+	for i := range scheme.ANSI {
+		argb, err := parseColor(values, fmt.Sprintf("color%d", i))
+		if err != nil {
+			return Scheme{}, err
+		}
+		scheme.ANSI[i] = argb
+	}
+	return scheme, scheme.fillSpecial(values, "background", "foreground", "cursor")
+}
+
+// fillSpecial reads background, foreground and cursor, falling back to ANSI black and white
+func (s *Scheme) fillSpecial(values map[string]string, background, foreground, cursor string) error {
+	s.Background, s.Foreground = s.ANSI[0], s.ANSI[7]
+	for key, dest := range map[string]*uint32{background: &s.Background, foreground: &s.Foreground} {
+		if values[key] == "" {
+			continue
+		}
+		argb, err := parseColor(values, key)
+		if err != nil {
+			return err
+		}
+		*dest = argb
+	}
+
+	s.Cursor = s.Foreground
+	if values[cursor] != "" {
+		argb, err := parseColor(values, cursor)
+		if err != nil {
+			return err
+		}
+		s.Cursor = argb
+	}
+	return nil
+}
+
+// plistNode is a generic XML element, enough to walk an iTerm2 property list
+type plistNode struct {
+	XMLName xml.Name
+	Content string      `xml:",chardata"`
+	Nodes   []plistNode `xml:",any"`
+}
+
+// parseITerm2 handles .itermcolors property lists
+func parseITerm2(data []byte) (Scheme, error) {
+	var root plistNode
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return Scheme{}, fmt.Errorf("invalid property list: %w", err)
+	}
+	if len(root.Nodes) == 0 || root.Nodes[0].XMLName.Local != "dict" {
+		return Scheme{}, fmt.Errorf("invalid property list: missing top-level dict")
+	}
+
+	colors := make(map[string]uint32)
+	entries := root.Nodes[0].Nodes
+	for i := 0; i+1 < len(entries); i += 2 {
+		if entries[i].XMLName.Local != "key" || entries[i+1].XMLName.Local != "dict" {
+			continue
+		}
+		colors[strings.TrimSpace(entries[i].Content)] = plistColor(entries[i+1])
+	}
+
+	scheme := Scheme{Format: FormatITerm2}
+	for i := range scheme.ANSI {
+		argb, ok := colors[fmt.Sprintf("Ansi %d Color", i)]
+		if !ok {
+			return Scheme{}, fmt.Errorf("missing Ansi %d Color", i)
+		}
+		scheme.ANSI[i] = argb
+	}
+
+	scheme.Background, scheme.Foreground = scheme.ANSI[0], scheme.ANSI[7]
+	if argb, ok := colors["Background Color"]; ok {
+		scheme.Background = argb
+	}
+	if argb, ok := colors["Foreground Color"]; ok {
+		scheme.Foreground = argb
+	}
+	scheme.Cursor = scheme.Foreground
+	if argb, ok := colors["Cursor Color"]; ok {
+		scheme.Cursor = argb
+	}
+	return scheme, nil
+}
+
+// plistColor reads the Red/Green/Blue Component reals of an iTerm2 color dict
+func plistColor(dict plistNode) uint32 {
+	components := make(map[string]float64)
+	for i := 0; i+1 < len(dict.Nodes); i += 2 {
+		value, err := strconv.ParseFloat(strings.TrimSpace(dict.Nodes[i+1].Content), 64)
+		if err != nil {
+			continue
+		}
+		components[strings.TrimSpace(dict.Nodes[i].Content)] = value
+	}
+
+	channel := func(key string) int {
+		return int(math.Round(math.Max(0, math.Min(1, components[key])) * 255))
+	}
+	return material.ArgbFromRgb(channel("Red Component"), channel("Green Component"), channel("Blue Component"))
+}
+
+func parseColor(values map[string]string, key string) (uint32, error) {
+	value, ok := values[key]
+	if !ok {
+		return 0, fmt.Errorf("missing %s", key)
+	}
+	argb, err := material.ArgbFromHex(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return argb | 0xff000000, nil
+}
+
+func firstOf(values map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if values[key] != "" {
+			return values[key]
+		}
+	}
+	return ""
+}
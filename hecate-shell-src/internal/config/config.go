@@ -60,3 +60,30 @@ func GetThemeFile() (string, error) {
 	}
 	return filepath.Join(configDir, "theme.json"), nil
 }
+
+// GetPaletteFile returns the path to palette.json, the full role palette behind theme.json
+func GetPaletteFile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "palette.json"), nil
+}
+
+// GetThemesDir returns the directory named theme snapshots are saved in
+func GetThemesDir() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "themes"), nil
+}
+
+// GetHistoryFile returns the path to history.json, the undo/redo theme history
+func GetHistoryFile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "history.json"), nil
+}
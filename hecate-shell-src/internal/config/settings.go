@@ -0,0 +1,191 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Theme defaults used when config.json doesn't set them
+const (
+	DefaultThemeBackend = "native"
+	DefaultThemeMode    = "dark"
+	DefaultThemeScheme  = "fidelity"
+)
+
+// ThemeSettings holds the "theme" section of config.json
+type ThemeSettings struct {
+	Backend string `json:"backend,omitempty"` // "native" (built-in) or "matugen"
+	Mode    string `json:"mode,omitempty"`    // "dark", "light" or "auto" (from wallpaper luminance)
+	Scheme  string `json:"scheme,omitempty"`  // Material scheme variant, e.g. "tonal-spot"
+
+	// Targets enables or disables template targets by name, overriding templates.json
+	Targets map[string]bool `json:"targets,omitempty"`
+
+	// Overrides adjusts generated colors before any template is rendered
+	Overrides *ThemeOverrides `json:"overrides,omitempty"`
+
+	// LiveTerminals recolors open terminals with escape sequences after a theme change
+	LiveTerminals *bool `json:"liveTerminals,omitempty"`
+}
+
+// RecolorsTerminals reports whether open terminals are recolored, which is the default
+func (s ThemeSettings) RecolorsTerminals() bool {
+	return s.LiveTerminals == nil || *s.LiveTerminals
+}
+
+// ThemeOverrides holds the "theme.overrides" section of config.json. Adjustments are
+// applied to every generated role first, then pinned roles replace their role outright.
+type ThemeOverrides struct {
+	HueShift float64  `json:"hueShift,omitempty"` // Degrees added to every hue
+	Chroma   *float64 `json:"chroma,omitempty"`   // Chroma multiplier; 0 makes everything grey
+	ToneBias float64  `json:"toneBias,omitempty"` // Added to every tone, clamped to 0-100
+
+	Roles map[string]string `json:"roles,omitempty"` // Role -> hex, pinned in both modes
+	Dark  map[string]string `json:"dark,omitempty"`  // Role -> hex, dark mode only
+	Light map[string]string `json:"light,omitempty"` // Role -> hex, light mode only
+}
+
+// GetUserConfigFile returns the path to config.json
+func GetUserConfigFile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "config.json"), nil
+}
+
+// LoadUserConfig reads config.json as a generic map, so sections the CLI
+// doesn't know about (bar, typography, ...) survive being written back
+func LoadUserConfig() (map[string]interface{}, error) {
+	configPath, err := GetUserConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config.json: %w", err)
+	}
+
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config.json: %w", err)
+	}
+	if cfg == nil {
+		cfg = make(map[string]interface{})
+	}
+	return cfg, nil
+}
+
+// SaveUserConfig writes config.json
+func SaveUserConfig(cfg map[string]interface{}) error {
+	configPath, err := GetUserConfigFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config.json: %w", err)
+	}
+	return nil
+}
+
+// LoadThemeSettings returns the theme section of config.json with defaults filled in.
+// A missing config.json is not an error.
+func LoadThemeSettings() (ThemeSettings, error) {
+	var settings ThemeSettings
+
+	cfg, err := LoadUserConfig()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return settings.withDefaults(), nil
+		}
+		return settings, err
+	}
+
+	if section, ok := cfg["theme"]; ok {
+		data, err := json.Marshal(section)
+		if err != nil {
+			return settings, err
+		}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return settings, fmt.Errorf("invalid theme section in config.json: %w", err)
+		}
+	}
+
+	return settings.withDefaults(), nil
+}
+
+// SaveThemeSettings writes the theme section back into config.json
+func SaveThemeSettings(settings ThemeSettings) error {
+	cfg, err := LoadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	var section map[string]interface{}
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	cfg["theme"] = section
+
+	return SaveUserConfig(cfg)
+}
+
+func (s ThemeSettings) withDefaults() ThemeSettings {
+	if s.Backend == "" {
+		s.Backend = DefaultThemeBackend
+	}
+	if s.Mode == "" {
+		s.Mode = DefaultThemeMode
+	}
+	if s.Scheme == "" {
+		s.Scheme = DefaultThemeScheme
+	}
+	return s
+}
+
+// GetWallpaperPath returns the wallpaper currently set in config.json
+func GetWallpaperPath() (string, error) {
+	cfg, err := LoadUserConfig()
+	if err != nil {
+		return "", err
+	}
+
+	wallpaper, _ := cfg["wallpaper"].(map[string]interface{})
+	path, _ := wallpaper["path"].(string)
+	if path == "" {
+		return "", fmt.Errorf("no wallpaper set in config.json")
+	}
+	return path, nil
+}
+
+// SetWallpaperPath points the wallpaper in config.json at path
+func SetWallpaperPath(path string) error {
+	cfg, err := LoadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	wallpaper, ok := cfg["wallpaper"].(map[string]interface{})
+	if !ok {
+		wallpaper = make(map[string]interface{})
+		cfg["wallpaper"] = wallpaper
+	}
+	wallpaper["path"] = path
+
+	return SaveUserConfig(cfg)
+}
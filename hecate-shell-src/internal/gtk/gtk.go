@@ -0,0 +1,171 @@
+package gtk
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// preferDarkKey is the GTK3 settings.ini key for dark theme variants
+const preferDarkKey = "gtk-application-prefer-dark-theme"
+
+// oldGTK3Header is the header of the gtk.css the GTK3 target used to overwrite
+const oldGTK3Header = "* GTK Colors - generated by HecateShell"
+
+// oldGTK3Comments are the comments of the template gtk.css used to be rendered from
+var oldGTK3Comments = []string{
+	"/* Destructive colors are basically error colors */",
+	"/* Follow material spec */",
+	"/* Follow material spec (also I think looks nicer) */",
+	"/* Use surface instead of background */",
+	"/* Make it more similar to Adwaita */",
+	"/* There is only like one application I know that uses this, and there isn't a good way to get",
+	"a color between surface and surface container so I think just giving this surface is a good enough fallback*/",
+}
+
+// oldGTK3Color matches the colors gtk.css used to define, which hecate-colors.css has now
+var oldGTK3Color = regexp.MustCompile(`^@define-color (destructive|error|accent|window|view|headerbar|sidebar|secondary_sidebar|card|overview|popover|dialog)_(bg|fg)_color\s`)
+
+// MigrateGTK3CSS removes what the GTK3 target used to render into gtk-3.0/gtk.css, which
+// now imports the generated colors instead. Lines added to it by hand are kept.
+func MigrateGTK3CSS() error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	path := filepath.Join(homeDir, ".config", "gtk-3.0", "gtk.css")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	header := slices.Index(lines[:min(len(lines), 5)], oldGTK3Header)
+	if header < 0 {
+		return nil
+	}
+	// Drop the header comment
+	start, end := header, header
+	for start > 0 && lines[start] != "/*" {
+		start--
+	}
+	for end < len(lines)-1 && lines[end] != "*/" {
+		end++
+	}
+	lines = append(lines[:start], lines[end+1:]...)
+
+	var kept []string
+	for _, l := range lines {
+		if slices.Contains(oldGTK3Comments, l) || oldGTK3Color.MatchString(l) {
+			continue
+		}
+		kept = append(kept, l)
+	}
+
+	content := strings.TrimLeft(strings.Join(kept, "\n"), "\n")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	fmt.Printf("Removed the old generated colors from %s\n", path)
+	return nil
+}
+
+// SetColorScheme makes GTK apps follow the theme's mode: GTK3 through
+// gtk-application-prefer-dark-theme in settings.ini, GTK4 and libadwaita through the
+// desktop color-scheme setting
+func SetColorScheme(isDark bool) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	value := "0"
+	if isDark {
+		value = "1"
+	}
+	settingsFile := filepath.Join(homeDir, ".config", "gtk-3.0", "settings.ini")
+	if err := setSetting(settingsFile, preferDarkKey, value); err != nil {
+		return err
+	}
+
+	// libadwaita warns about gtk-application-prefer-dark-theme, so GTK4 only gets color-scheme.
+	// Skip desktops without gsettings or the GNOME schemas.
+	if exec.Command("gsettings", "writable", "org.gnome.desktop.interface", "color-scheme").Run() != nil {
+		return nil
+	}
+	scheme := "default"
+	if isDark {
+		scheme = "prefer-dark"
+	}
+	if out, err := exec.Command("gsettings", "set", "org.gnome.desktop.interface", "color-scheme", scheme).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set color-scheme: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// setSetting sets a key in the [Settings] section of a GTK settings.ini, adding the
+// section or the file if they are missing
+func setSetting(path, key, value string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	setting := key + "=" + value
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if data == nil {
+		lines = nil
+	}
+
+	section := -1
+	set := false
+	for i, l := range lines {
+		trimmed := strings.TrimSpace(l)
+		if strings.HasPrefix(trimmed, "[") {
+			if section >= 0 {
+				break // End of [Settings]
+			}
+			if trimmed == "[Settings]" {
+				section = i
+			}
+			continue
+		}
+		if section < 0 {
+			continue
+		}
+		if k, _, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(k) == key {
+			if trimmed == setting {
+				return nil
+			}
+			lines[i] = setting
+			set = true
+			break
+		}
+	}
+
+	if !set {
+		if section >= 0 {
+			lines = append(lines[:section+1], append([]string{setting}, lines[section+1:]...)...)
+		} else {
+			lines = append(lines, "[Settings]", setting)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package gtk
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testdata/old-gtk.css is the gtk.css the GTK3 target used to render
+func TestMigrateGTK3CSS(t *testing.T) {
+	old, err := os.ReadFile(filepath.Join("testdata", "old-gtk.css"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const userRules = "window.background {\n  border-radius: 12px;\n}\n"
+	const migrated = "@import url(\"hecate-colors.css\");\n@define-color accent_bg_color #ff0000;\n" + userRules
+
+	tests := []struct {
+		name string
+		css  string // Empty for a missing gtk.css
+		want string // Empty when gtk.css must not exist
+	}{
+		{
+			name: "generated only",
+			css:  string(old),
+			want: "",
+		},
+		{
+			name: "user rules after the generated colors",
+			css:  string(old) + "\n" + userRules,
+			want: userRules,
+		},
+		{
+			name: "already migrated",
+			css:  migrated,
+			want: migrated,
+		},
+		{
+			name: "missing",
+		},
+	}
+
+	for _, tt := range tests {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		path := filepath.Join(home, ".config", "gtk-3.0", "gtk.css")
+		if tt.css != "" {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(tt.css), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := MigrateGTK3CSS(); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if tt.css == "" {
+			if !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("%s: gtk.css was created", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,26 +1,65 @@
 package hooks
 
 import (
+	"errors"
 	"fmt"
-	"os/exec"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/terminal"
+	"hecate-shell/internal/theme"
 )
 
-// RunPostThemeHooks runs commands that need to execute after theme generation
+// RunPostThemeHooks recolors terminals, reloads applications and runs the user's
+// post-theme hooks after theme generation
 func RunPostThemeHooks() {
-	// Update pywalfox (Firefox theming)
-	if err := runPywalfoxUpdate(); err != nil {
-		fmt.Printf("Warning: pywalfox update failed: %v\n", err)
+	// Recolor open terminals and save the colors for new shells
+	if err := applyTerminalSequences(); err != nil {
+		fmt.Printf("Warning: failed to recolor terminals: %v\n", err)
 	}
+
+	// Push the rendered templates into running applications
+	reloadApps()
+
+	RunUserHooks(PostTheme)
 }
 
-// runPywalfoxUpdate calls pywalfox update to apply new colors to Firefox
-func runPywalfoxUpdate() error {
-	// Check if pywalfox is installed
-	if _, err := exec.LookPath("pywalfox"); err != nil {
-		// pywalfox not installed, skip silently
+// applyTerminalSequences writes the theme's terminal colors as escape sequences to the
+// sequences cache file and, unless theme.liveTerminals is false, to open shells
+func applyTerminalSequences() error {
+	palette, err := theme.LoadPalette()
+	if err != nil {
+		if errors.Is(err, theme.ErrNoPalette) {
+			return nil
+		}
+		return err
+	}
+	data, err := palette.Data()
+	if err != nil {
+		return err
+	}
+
+	seq, err := terminal.Sequences(data.Default())
+	if err != nil {
+		return err
+	}
+	if err := terminal.WriteCache(seq); err != nil {
+		return err
+	}
+
+	settings, err := config.LoadThemeSettings()
+	if err != nil {
+		return err
+	}
+	if !settings.RecolorsTerminals() {
 		return nil
 	}
 
-	cmd := exec.Command("pywalfox", "update")
-	return cmd.Run()
+	recolored, err := terminal.Broadcast(seq)
+	if err != nil {
+		return err
+	}
+	if recolored > 0 {
+		fmt.Printf("Recolored %d open terminal(s)\n", recolored)
+	}
+	return nil
 }
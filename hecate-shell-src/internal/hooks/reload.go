@@ -0,0 +1,235 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"hecate-shell/internal/templates"
+)
+
+// reloader pushes a freshly rendered target into running instances of an application
+type reloader struct {
+	name    string        // Application name shown in the summary
+	target  string        // Template target; the reloader only runs if it was rendered
+	tool    string        // Executable the reloader needs, if any
+	timeout time.Duration // How long the reload may take
+
+	// reload applies the rendered output and returns how many instances it reached
+	reload func(ctx context.Context, output string) (int, error)
+}
+
+// reloaders is the reload registry, keyed by template target. Applications that watch
+// their own config files (or read it on every launch, like fuzzel and rofi) aren't listed.
+var reloaders = []reloader{
+	{name: "kitty", target: "hecate_kitty", tool: "kitty", timeout: 5 * time.Second, reload: reloadKitty},
+	{name: "neovim", target: "hecate_nvim", tool: "nvim", timeout: 5 * time.Second, reload: reloadNeovim},
+	{name: "spicetify", target: "hecate_spicetify", tool: "spicetify", timeout: 30 * time.Second, reload: reloadSpicetify},
+	{name: "Vencord", target: "hecate_discord", timeout: time.Second, reload: touch},
+	{name: "pywalfox", target: "hecate_pywalfox", tool: "pywalfox", timeout: 10 * time.Second, reload: reloadPywalfox},
+	{name: "mako", target: "hecate_mako", tool: "makoctl", timeout: 5 * time.Second, reload: reloadDaemon("mako", "makoctl", "reload")},
+	{name: "dunst", target: "hecate_dunst", tool: "dunstctl", timeout: 5 * time.Second, reload: reloadDaemon("dunst", "dunstctl", "reload")},
+	{name: "SwayNC", target: "hecate_swaync", tool: "swaync-client", timeout: 5 * time.Second, reload: reloadDaemon("swaync", "swaync-client", "--reload-css")},
+	{name: "tmux", target: "hecate_tmux", tool: "tmux", timeout: 5 * time.Second, reload: reloadTmux},
+	{name: "fish", target: "hecate_fish", tool: "fish", timeout: 5 * time.Second, reload: reloadFish},
+}
+
+// reloadApps runs the reloaders of the rendered targets and prints a summary
+func reloadApps() {
+	rendered, err := templates.Rendered()
+	if err != nil {
+		fmt.Printf("Warning: failed to reload applications: %v\n", err)
+		return
+	}
+	outputs := make(map[string]string)
+	for _, target := range rendered {
+		outputs[target.Name] = target.Output
+	}
+
+	var reloaded []string
+	for _, r := range reloaders {
+		output, ok := outputs[r.target]
+		if !ok {
+			continue
+		}
+		if r.tool != "" {
+			if _, err := exec.LookPath(r.tool); err != nil {
+				continue
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		n, err := r.reload(ctx, output)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", r.timeout)
+		}
+		cancel()
+
+		switch {
+		case err != nil:
+			fmt.Printf("Warning: failed to reload %s: %v\n", r.name, err)
+		case n > 1:
+			reloaded = append(reloaded, fmt.Sprintf("%s (%d)", r.name, n))
+		case n == 1:
+			reloaded = append(reloaded, r.name)
+		}
+	}
+
+	if len(reloaded) > 0 {
+		fmt.Printf("Reloaded %s\n", strings.Join(reloaded, ", "))
+	}
+}
+
+// run runs a command, adding its output to the error if it fails
+func run(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	// Don't wait for children of a killed command that still hold its output open
+	cmd.WaitDelay = time.Second
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// userProcesses returns the IDs of the current user's processes with the given command name
+func userProcesses(name string) []int {
+	var pids []int
+	dirs, _ := filepath.Glob("/proc/[0-9]*")
+	uid := uint32(os.Getuid())
+	for _, dir := range dirs {
+		var st syscall.Stat_t
+		if err := syscall.Stat(dir, &st); err != nil || st.Uid != uid {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+		if err != nil || strings.TrimSpace(string(comm)) != name {
+			continue
+		}
+		if pid, err := strconv.Atoi(filepath.Base(dir)); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// reloadKitty makes every kitty instance re-read its config, which includes the colors.
+// When run from a kitty with remote control, its windows are also recolored right away.
+func reloadKitty(ctx context.Context, output string) (int, error) {
+	reloaded := 0
+	for _, pid := range userProcesses("kitty") {
+		if syscall.Kill(pid, syscall.SIGUSR1) == nil {
+			reloaded++
+		}
+	}
+
+	if socket := os.Getenv("KITTY_LISTEN_ON"); socket != "" {
+		if err := run(ctx, "kitty", "@", "--to", socket, "set-colors", "--all", "--configured", output); err != nil {
+			return reloaded, err
+		}
+	}
+	return reloaded, nil
+}
+
+// reloadNeovim re-runs the colorscheme spec in every Neovim listening on a socket in
+// $XDG_RUNTIME_DIR (nvim.<pid>.<n>, the default for Neovim 0.9+)
+func reloadNeovim(ctx context.Context, output string) (int, error) {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return 0, nil
+	}
+	sockets, err := filepath.Glob(filepath.Join(runtimeDir, "nvim.*"))
+	if err != nil {
+		return 0, err
+	}
+
+	// <Cmd> runs without leaving the current mode; "<" would start a key name
+	keys := fmt.Sprintf(`<Cmd>lua local spec = dofile(%s) if spec and spec[1] and spec[1].config then spec[1].config() end<CR>`,
+		strings.ReplaceAll(strconv.Quote(output), "<", "<lt>"))
+
+	reloaded := 0
+	var errs []error
+	for _, socket := range sockets {
+		// Skip sockets left behind by Neovims that crashed
+		pid, _, _ := strings.Cut(strings.TrimPrefix(filepath.Base(socket), "nvim."), ".")
+		if _, err := os.Stat(filepath.Join("/proc", pid)); err != nil {
+			continue
+		}
+		if err := run(ctx, "nvim", "--server", socket, "--remote-send", keys); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reloaded++
+	}
+	return reloaded, errors.Join(errs...)
+}
+
+// reloadSpicetify applies the color.ini to Spotify
+func reloadSpicetify(ctx context.Context, output string) (int, error) {
+	if err := run(ctx, "spicetify", "apply"); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// touch updates a file's modification time for applications that watch it, like Vencord
+func touch(ctx context.Context, output string) (int, error) {
+	now := time.Now()
+	if err := os.Chtimes(output, now, now); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// reloadPywalfox applies the colors.json to Firefox
+func reloadPywalfox(ctx context.Context, output string) (int, error) {
+	if err := run(ctx, "pywalfox", "update"); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// reloadDaemon returns a reloader for a daemon that doesn't watch its config files.
+// The command only runs while the daemon does.
+func reloadDaemon(process string, command ...string) func(context.Context, string) (int, error) {
+	return func(ctx context.Context, output string) (int, error) {
+		if len(userProcesses(process)) == 0 {
+			return 0, nil
+		}
+		if err := run(ctx, command[0], command[1:]...); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+}
+
+// reloadTmux sources the colors into the running tmux server
+func reloadTmux(ctx context.Context, output string) (int, error) {
+	if exec.CommandContext(ctx, "tmux", "has-session").Run() != nil {
+		return 0, nil // No server running
+	}
+	if err := run(ctx, "tmux", "source-file", output); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// reloadFish sets the universal color variables, which open fish shells pick up immediately
+func reloadFish(ctx context.Context, output string) (int, error) {
+	// Single-quoted fish string: only \ and ' are special
+	quoted := "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(output) + "'"
+	if err := run(ctx, "fish", "-c", "source "+quoted); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
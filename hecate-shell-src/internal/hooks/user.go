@@ -0,0 +1,170 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/material"
+)
+
+// User hook stages. The executables of a stage live in <hooks dir>/<stage>.d.
+const (
+	PreTheme      = "pre-theme"      // Before a theme is generated or restored
+	PostTheme     = "post-theme"     // After the new theme is rendered and applied
+	PostWallpaper = "post-wallpaper" // After the wallpaper changed
+)
+
+// userHookTimeout is how long a single user hook may run
+const userHookTimeout = 30 * time.Second
+
+// GetHooksDir returns the directory of user hook scripts
+func GetHooksDir() (string, error) {
+	shellDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(shellDir, "hooks"), nil
+}
+
+// RunUserHooks runs the executables of a stage in name order with the theme colors as
+// HECATE_COLOR_<ROLE> and the wallpaper as HECATE_WALLPAPER in their environment. A hook
+// that fails or times out is reported and the rest still run.
+func RunUserHooks(stage string) {
+	hooksDir, err := GetHooksDir()
+	if err != nil {
+		fmt.Printf("Warning: failed to find %s hooks: %v\n", stage, err)
+		return
+	}
+	dir := filepath.Join(hooksDir, stage+".d")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("Warning: failed to read %s: %v\n", dir, err)
+		}
+		return
+	}
+
+	var scripts []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if info.Mode()&0111 == 0 {
+			fmt.Printf("Warning: skipping %s hook %s: not executable\n", stage, name)
+			continue
+		}
+		scripts = append(scripts, name)
+	}
+	if len(scripts) == 0 {
+		return
+	}
+
+	env, err := hookEnv()
+	if err != nil {
+		fmt.Printf("Warning: %s hooks get no colors: %v\n", stage, err)
+	}
+
+	failed := 0
+	for _, name := range scripts {
+		if err := runUserHook(filepath.Join(dir, name), env); err != nil {
+			fmt.Printf("Warning: %s hook %s failed: %v\n", stage, name, err)
+			failed++
+		}
+	}
+	fmt.Printf("Ran %d %s hook(s), %d failed\n", len(scripts), stage, failed)
+}
+
+// runUserHook runs a hook with the timeout, showing its output
+func runUserHook(path string, env []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), userHookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = filepath.Dir(path)
+	cmd.Env = env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Kill the whole process group on timeout, so a script's children don't outlive it
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", userHookTimeout)
+	}
+	return err
+}
+
+// hookEnv returns the environment for user hooks: the current one plus every color of
+// theme.json and the wallpaper path. The environment is returned even if theme.json
+// can't be read.
+func hookEnv() ([]string, error) {
+	env := os.Environ()
+	if wallpaper, err := config.GetWallpaperPath(); err == nil {
+		env = append(env, "HECATE_WALLPAPER="+wallpaper)
+	}
+
+	themeFile, err := config.GetThemeFile()
+	if err != nil {
+		return env, err
+	}
+	data, err := os.ReadFile(themeFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return env, nil
+		}
+		return env, fmt.Errorf("failed to read theme.json: %w", err)
+	}
+	var keys map[string]any
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return env, fmt.Errorf("failed to parse theme.json: %w", err)
+	}
+
+	for key, value := range keys {
+		hex, ok := value.(string)
+		if !ok || !strings.HasPrefix(hex, "#") {
+			continue
+		}
+		if _, err := material.ArgbFromHex(hex); err != nil {
+			continue // Not a color
+		}
+		env = append(env, "HECATE_COLOR_"+envName(key)+"="+hex)
+	}
+	return env, nil
+}
+
+// envName turns a theme.json key into an environment variable name: primaryContainer
+// becomes PRIMARY_CONTAINER
+func envName(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		if r == '-' {
+			r = '_'
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"hecate-shell/internal/config"
+	"hecate-shell/internal/templates"
 )
 
 // Dependencies for HecateShell
@@ -21,7 +22,6 @@ var Dependencies = []string{
 	"cava",
 	"pipewire",
 	"wireplumber",
-	"matugen-bin",
 	"ttf-jetbrains-mono-nerd",
 }
 
@@ -436,7 +436,6 @@ func GetMissingDependencies() []string {
 		"cava":                    "cava",
 		"pipewire":                "pipewire",
 		"wireplumber":             "wpctl",
-		"matugen-bin":             "matugen",
 		"ttf-jetbrains-mono-nerd": "", // Font, no binary to check
 	}
 
@@ -488,6 +487,12 @@ func UpdateShell(progress ProgressCallback) TaskResult {
 		progress(1, 2, "Pulling latest changes...")
 	}
 
+	// Keep local template edits from blocking or being lost in the pull
+	preserved, err := preserveTemplateEdits(configDir)
+	if err != nil {
+		return TaskResult{Success: false, Error: err}
+	}
+
 	// Git pull
 	cmd := exec.Command("git", "-C", configDir, "pull", "origin", "main")
 	output, err := cmd.CombinedOutput()
@@ -503,7 +508,54 @@ func UpdateShell(progress ProgressCallback) TaskResult {
 		progress(2, 2, "Updated!")
 	}
 
-	return TaskResult{Success: true, Message: "HecateShell updated successfully"}
+	message := "HecateShell updated successfully"
+	if len(preserved) > 0 {
+		message += fmt.Sprintf(" (local template edits moved to templates/: %s)", strings.Join(preserved, ", "))
+	}
+	return TaskResult{Success: true, Message: message}
+}
+
+// preserveTemplateEdits moves local edits of shipped templates into the user templates
+// dir, where they shadow the shipped files, and restores the shipped files so they can be
+// updated. Templates that already have a user copy are left alone.
+func preserveTemplateEdits(configDir string) ([]string, error) {
+	output, err := exec.Command("git", "-C", configDir, "diff", "--name-only", "HEAD", "--", "config/templates").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for template edits: %w", err)
+	}
+
+	userDir, err := templates.GetUserTemplateDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var preserved []string
+	for _, file := range strings.Fields(string(output)) {
+		rel, err := filepath.Rel(filepath.Join("config", "templates"), file)
+		if err != nil {
+			continue
+		}
+		src := filepath.Join(configDir, file)
+		dst := filepath.Join(userDir, rel)
+		if _, err := os.Stat(src); err != nil {
+			continue // Deleted locally; the pull restores it
+		}
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return preserved, fmt.Errorf("failed to create user templates directory: %w", err)
+		}
+		if err := copyFile(src, dst); err != nil {
+			return preserved, fmt.Errorf("failed to preserve %s: %w", rel, err)
+		}
+		if err := exec.Command("git", "-C", configDir, "checkout", "HEAD", "--", file).Run(); err != nil {
+			return preserved, fmt.Errorf("failed to restore %s: %w", file, err)
+		}
+		preserved = append(preserved, rel)
+	}
+	return preserved, nil
 }
 
 // BackupDotfile creates a backup of an existing dotfile config
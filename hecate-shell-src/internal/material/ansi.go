@@ -0,0 +1,97 @@
+package material
+
+import (
+	"math"
+	"strconv"
+)
+
+// ansiHues are the six chromatic ANSI colors, in slot order 1-6, before harmonization
+var ansiHues = [6]uint32{
+	0xfff44336, // Red
+	0xff4caf50, // Green
+	0xffffc107, // Yellow
+	0xff2196f3, // Blue
+	0xffe040fb, // Magenta
+	0xff00bcd4, // Cyan
+}
+
+// ANSI contrast requirements against the terminal background
+const (
+	ansiTextContrast = 4.5 // Every color used for text
+	ansiDimContrast  = 3.0 // Bright black, used for comments and other dimmed text
+)
+
+// ansiChroma caps how colorful the chromatic slots get, so they read as terminal colors
+// rather than neon whatever the source chroma
+const (
+	ansiChroma       = 48.0
+	ansiBrightChroma = 56.0
+)
+
+// TerminalRoles are the role names of the 16 ANSI colors, term0 (black) to term15 (bright white)
+var TerminalRoles = func() [16]string {
+	var names [16]string
+	for i := range names {
+		names[i] = "term" + strconv.Itoa(i)
+	}
+	return names
+}()
+
+// terminalColors builds the 16 ANSI colors: hue-correct red, green, yellow, blue, magenta
+// and cyan harmonized toward the source, with grays from the neutral palette. Every
+// color except black meets WCAG contrast against the scheme's background; bright colors
+// have more contrast than normal ones in both modes.
+func (s *Scheme) terminalColors() [16]uint32 {
+	if s.terminal != nil {
+		return *s.terminal
+	}
+
+	bgTone := HctFromArgb(s.tone(s.Neutral, 6, 98)).Tone()
+	normalTone, brightTone := 70.0, 80.0
+	if !s.IsDark {
+		normalTone, brightTone = 40.0, 30.0
+	}
+
+	var colors [16]uint32
+	for i, seed := range ansiHues {
+		hue := HctFromArgb(Harmonize(seed, s.Source.Argb())).Hue()
+		colors[i+1] = readableColor(hue, ansiChroma, normalTone, bgTone, ansiTextContrast)
+		colors[i+9] = readableColor(hue, ansiBrightChroma, brightTone, bgTone, ansiTextContrast)
+	}
+
+	gray := func(dark, light, ratio float64) uint32 {
+		tone := dark
+		if !s.IsDark {
+			tone = light
+		}
+		return readableColor(s.Neutral.Hue(), s.Neutral.Chroma(), tone, bgTone, ratio)
+	}
+	colors[0] = s.Neutral.Tone(20) // Black backs text as often as it is text, so it isn't checked
+	colors[7] = gray(80, 30, ansiTextContrast)
+	colors[8] = gray(60, 50, ansiDimContrast)
+	colors[15] = gray(95, 10, ansiTextContrast)
+
+	s.terminal = &colors
+	return colors
+}
+
+// readableColor returns the color at tone, moved away from the background tone until it
+// has at least ratio contrast
+func readableColor(hue, chroma, tone, bgTone, ratio float64) uint32 {
+	if RatioOfTones(tone, bgTone) < ratio {
+		if bgTone < 50 {
+			if lighter := LighterTone(bgTone, ratio); lighter >= 0 {
+				tone = math.Max(tone, lighter)
+			} else {
+				tone = 100
+			}
+		} else {
+			if darker := DarkerTone(bgTone, ratio); darker >= 0 {
+				tone = math.Min(tone, darker)
+			} else {
+				tone = 0
+			}
+		}
+	}
+	return NewHct(hue, chroma, tone).Argb()
+}
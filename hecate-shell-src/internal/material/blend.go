@@ -0,0 +1,36 @@
+package material
+
+import "math"
+
+// Harmonize rotates a design color's hue up to 15 degrees toward a source color, keeping
+// its chroma and tone, so fixed colors such as "success green" sit well with the scheme
+func Harmonize(design, source uint32) uint32 {
+	from := HctFromArgb(design)
+	to := HctFromArgb(source)
+
+	rotation := math.Min(differenceDegrees(from.Hue(), to.Hue())*0.5, 15.0)
+	hue := sanitizeDegrees(from.Hue() + rotation*rotationDirection(from.Hue(), to.Hue()))
+	return NewHct(hue, from.Chroma(), from.Tone()).Argb()
+}
+
+// rotationDirection returns 1 if the shortest way from one hue to another is increasing
+func rotationDirection(from, to float64) float64 {
+	if sanitizeDegrees(to-from) <= 180.0 {
+		return 1.0
+	}
+	return -1.0
+}
+
+// SemanticColor is a fixed-meaning color that is harmonized toward each scheme's source
+type SemanticColor struct {
+	Name string // Role name; the scheme also provides on_<name>, <name>_container and on_<name>_container
+	Seed uint32 // Color before harmonization
+}
+
+// SemanticColors lists the semantic colors every scheme provides besides error, which
+// keeps Material's own unharmonized palette
+var SemanticColors = []SemanticColor{
+	{"success", 0xff4caf50}, // Green
+	{"warning", 0xffffb300}, // Amber
+	{"info", 0xff2196f3},    // Blue
+}
@@ -0,0 +1,119 @@
+package material
+
+import "math"
+
+// viewingConditions holds the CAM16 parameters derived from the viewing environment
+type viewingConditions struct {
+	n, aw, nbb, ncb, c, nc float64
+	rgbD                   [3]float64
+	fl, fLRoot, z          float64
+}
+
+// defaultViewingConditions matches sRGB viewing: D65 white point, 200 lux, mid-gray background
+var defaultViewingConditions = makeViewingConditions(whitePointD65, 200.0/math.Pi*YFromLstar(50.0)/100.0, 50.0, 2.0, false)
+
+func makeViewingConditions(whitePoint [3]float64, adaptingLuminance, backgroundLstar, surround float64, discountingIlluminant bool) viewingConditions {
+	backgroundLstar = math.Max(0.1, backgroundLstar)
+
+	rW := whitePoint[0]*0.401288 + whitePoint[1]*0.650173 + whitePoint[2]*-0.051461
+	gW := whitePoint[0]*-0.250268 + whitePoint[1]*1.204414 + whitePoint[2]*0.045854
+	bW := whitePoint[0]*-0.002079 + whitePoint[1]*0.048952 + whitePoint[2]*0.953127
+
+	f := 0.8 + surround/10.0
+	var c float64
+	if f >= 0.9 {
+		c = lerp(0.59, 0.69, (f-0.9)*10.0)
+	} else {
+		c = lerp(0.525, 0.59, (f-0.8)*10.0)
+	}
+
+	d := 1.0
+	if !discountingIlluminant {
+		d = f * (1.0 - (1.0/3.6)*math.Exp((-adaptingLuminance-42.0)/92.0))
+	}
+	d = clampFloat(0, 1, d)
+
+	rgbD := [3]float64{
+		d*(100.0/rW) + 1.0 - d,
+		d*(100.0/gW) + 1.0 - d,
+		d*(100.0/bW) + 1.0 - d,
+	}
+
+	k := 1.0 / (5.0*adaptingLuminance + 1.0)
+	k4 := k * k * k * k
+	k4F := 1.0 - k4
+	fl := k4*adaptingLuminance + 0.1*k4F*k4F*math.Cbrt(5.0*adaptingLuminance)
+	n := YFromLstar(backgroundLstar) / whitePoint[1]
+	z := 1.48 + math.Sqrt(n)
+	nbb := 0.725 / math.Pow(n, 0.2)
+
+	var rgbA [3]float64
+	for i, w := range [3]float64{rW, gW, bW} {
+		factor := math.Pow(fl*rgbD[i]*w/100.0, 0.42)
+		rgbA[i] = 400.0 * factor / (factor + 27.13)
+	}
+	aw := (2.0*rgbA[0] + rgbA[1] + 0.05*rgbA[2]) * nbb
+
+	return viewingConditions{
+		n: n, aw: aw, nbb: nbb, ncb: nbb, c: c, nc: f,
+		rgbD: rgbD, fl: fl, fLRoot: math.Pow(fl, 0.25), z: z,
+	}
+}
+
+// cam16 is a color in the CAM16 color appearance model
+type cam16 struct {
+	hue, chroma, j, q, m, s float64
+	jstar, astar, bstar     float64
+}
+
+// cam16FromArgb converts an ARGB color to CAM16 under default viewing conditions
+func cam16FromArgb(argb uint32) cam16 {
+	xyz := XyzFromArgb(argb)
+	return cam16FromXyz(xyz[0], xyz[1], xyz[2], defaultViewingConditions)
+}
+
+func cam16FromXyz(x, y, z float64, vc viewingConditions) cam16 {
+	rC := 0.401288*x + 0.650173*y - 0.051461*z
+	gC := -0.250268*x + 1.204414*y + 0.045854*z
+	bC := -0.002079*x + 0.048952*y + 0.953127*z
+
+	adapt := func(component, d float64) float64 {
+		af := math.Pow(vc.fl*math.Abs(component*d)/100.0, 0.42)
+		return signum(component*d) * 400.0 * af / (af + 27.13)
+	}
+	rA := adapt(rC, vc.rgbD[0])
+	gA := adapt(gC, vc.rgbD[1])
+	bA := adapt(bC, vc.rgbD[2])
+
+	a := (11.0*rA + -12.0*gA + bA) / 11.0
+	b := (rA + gA - 2.0*bA) / 9.0
+	u := (20.0*rA + 20.0*gA + 21.0*bA) / 20.0
+	p2 := (40.0*rA + 20.0*gA + bA) / 20.0
+
+	hue := sanitizeDegrees(math.Atan2(b, a) * 180.0 / math.Pi)
+	hueRadians := hue * math.Pi / 180.0
+
+	ac := p2 * vc.nbb
+	j := 100.0 * math.Pow(ac/vc.aw, vc.c*vc.z)
+	q := 4.0 / vc.c * math.Sqrt(j/100.0) * (vc.aw + 4.0) * vc.fLRoot
+
+	huePrime := hue
+	if hue < 20.14 {
+		huePrime = hue + 360
+	}
+	eHue := 0.25 * (math.Cos(huePrime*math.Pi/180.0+2.0) + 3.8)
+	p1 := 50000.0 / 13.0 * eHue * vc.nc * vc.ncb
+	t := p1 * math.Hypot(a, b) / (u + 0.305)
+	alpha := math.Pow(t, 0.9) * math.Pow(1.64-math.Pow(0.29, vc.n), 0.73)
+	c := alpha * math.Sqrt(j/100.0)
+	m := c * vc.fLRoot
+	s := 50.0 * math.Sqrt(alpha*vc.c/(vc.aw+4.0))
+
+	jstar := (1.0 + 100.0*0.007) * j / (1.0 + 0.007*j)
+	mstar := 1.0 / 0.0228 * math.Log1p(0.0228*m)
+
+	return cam16{
+		hue: hue, chroma: c, j: j, q: q, m: m, s: s,
+		jstar: jstar, astar: mstar * math.Cos(hueRadians), bstar: mstar * math.Sin(hueRadians),
+	}
+}
@@ -0,0 +1,108 @@
+package material
+
+import "math"
+
+// ContrastRatio returns the WCAG contrast ratio (1-21) between two colors
+func ContrastRatio(a, b uint32) float64 {
+	return ratioOfYs(XyzFromArgb(a)[1], XyzFromArgb(b)[1])
+}
+
+// RatioOfTones returns the WCAG contrast ratio between two tones
+func RatioOfTones(a, b float64) float64 {
+	a = clampFloat(0, 100, a)
+	b = clampFloat(0, 100, b)
+	return ratioOfYs(YFromLstar(a), YFromLstar(b))
+}
+
+func ratioOfYs(y1, y2 float64) float64 {
+	lighter := math.Max(y1, y2)
+	darker := math.Min(y1, y2)
+	return (lighter + 5.0) / (darker + 5.0)
+}
+
+// LighterTone returns a tone at least ratio lighter than tone, or -1 if none exists
+func LighterTone(tone, ratio float64) float64 {
+	if tone < 0.0 || tone > 100.0 {
+		return -1.0
+	}
+
+	darkY := YFromLstar(tone)
+	lightY := ratio*(darkY+5.0) - 5.0
+	realContrast := ratioOfYs(lightY, darkY)
+	delta := math.Abs(realContrast - ratio)
+	if realContrast < ratio && delta > 0.04 {
+		return -1.0
+	}
+
+	// Ensure gamut mapping, which requires a 'range' on tone, will still result in the correct ratio
+	value := LstarFromY(lightY) + 0.4
+	if value < 0 || value > 100 {
+		return -1.0
+	}
+	return value
+}
+
+// DarkerTone returns a tone at least ratio darker than tone, or -1 if none exists
+func DarkerTone(tone, ratio float64) float64 {
+	if tone < 0.0 || tone > 100.0 {
+		return -1.0
+	}
+
+	lightY := YFromLstar(tone)
+	darkY := ((lightY + 5.0) / ratio) - 5.0
+	realContrast := ratioOfYs(lightY, darkY)
+	delta := math.Abs(realContrast - ratio)
+	if realContrast < ratio && delta > 0.04 {
+		return -1.0
+	}
+
+	value := LstarFromY(darkY) - 0.4
+	if value < 0 || value > 100 {
+		return -1.0
+	}
+	return value
+}
+
+// lighterToneUnsafe is LighterTone, falling back to white
+func lighterToneUnsafe(tone, ratio float64) float64 {
+	if lighter := LighterTone(tone, ratio); lighter >= 0 {
+		return lighter
+	}
+	return 100.0
+}
+
+// darkerToneUnsafe is DarkerTone, falling back to black
+func darkerToneUnsafe(tone, ratio float64) float64 {
+	if darker := DarkerTone(tone, ratio); darker >= 0 {
+		return darker
+	}
+	return 0.0
+}
+
+// tonePrefersLightForeground reports whether text on this tone reads better light.
+// Tones 50-59 are technically better with dark text but look nicer with light text.
+func tonePrefersLightForeground(tone float64) bool {
+	return math.Round(tone) < 60.0
+}
+
+// foregroundTone picks the tone for content drawn on a background tone, favoring
+// light content on darker backgrounds.
+func foregroundTone(bgTone, ratio float64) float64 {
+	lighterTone := lighterToneUnsafe(bgTone, ratio)
+	darkerTone := darkerToneUnsafe(bgTone, ratio)
+	lighterRatio := RatioOfTones(lighterTone, bgTone)
+	darkerRatio := RatioOfTones(darkerTone, bgTone)
+
+	if tonePrefersLightForeground(bgTone) {
+		negligibleDifference := math.Abs(lighterRatio-darkerRatio) < 0.1 && lighterRatio < ratio && darkerRatio < ratio
+		if lighterRatio >= ratio || lighterRatio >= darkerRatio || negligibleDifference {
+			return lighterTone
+		}
+		return darkerTone
+	}
+
+	if darkerRatio >= ratio || darkerRatio >= lighterRatio {
+		return darkerTone
+	}
+	return lighterTone
+}
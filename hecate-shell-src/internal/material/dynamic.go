@@ -0,0 +1,179 @@
+package material
+
+import "math"
+
+// dynamicColor is a color role whose tone is adjusted for contrast against the role it
+// is drawn on, like DynamicColor in material-color-utilities at the standard contrast level
+type dynamicColor struct {
+	palette func(s *Scheme) *TonalPalette
+	tone    func(s *Scheme) float64 // Tone before contrast adjustments
+
+	isBackground     bool                          // Other roles are drawn on it
+	background       func(s *Scheme) *dynamicColor // Role it must contrast with, if any
+	secondBackground func(s *Scheme) *dynamicColor // Second role it must contrast with, if any
+	contrast         float64                       // Minimum contrast ratio with the background
+	pair             func(s *Scheme) toneDeltaPair // Role it must keep a tone distance from, if any
+}
+
+// tonePolarity says which role of a toneDeltaPair sits nearer the background
+type tonePolarity int
+
+const (
+	polarityNearer  tonePolarity = iota // a is nearer the background in both modes
+	polarityLighter                     // a is the lighter of the two
+)
+
+// toneDeltaPair keeps two roles at least delta tones apart, such as a container and
+// its accent, moving the farther one away from the background when they're too close
+type toneDeltaPair struct {
+	a, b         *dynamicColor
+	delta        float64
+	polarity     tonePolarity
+	stayTogether bool // Move both out of the 50-59 tone range, not just the one in it
+}
+
+// argb resolves the role's color in a scheme
+func (c *dynamicColor) argb(s *Scheme) uint32 {
+	return toneOf(c.palette(s), c.getTone(s))
+}
+
+// getTone resolves the role's tone in a scheme, meeting its contrast against the
+// background and the distance to its pair
+func (c *dynamicColor) getTone(s *Scheme) float64 {
+	if c.pair != nil {
+		return c.pairTone(s)
+	}
+
+	answer := c.tone(s)
+	if c.background == nil {
+		return answer
+	}
+
+	bgTone := c.background(s).getTone(s)
+	if RatioOfTones(bgTone, answer) < c.contrast {
+		answer = foregroundTone(bgTone, c.contrast)
+	}
+
+	// Backgrounds avoid tones 50-59, which are too light for light text and too dark for dark text
+	if c.isBackground && 50 <= answer && answer < 60 {
+		if RatioOfTones(49, bgTone) >= c.contrast {
+			answer = 49
+		} else {
+			answer = 60
+		}
+	}
+
+	if c.secondBackground == nil {
+		return answer
+	}
+
+	bgTone1 := bgTone
+	bgTone2 := c.secondBackground(s).getTone(s)
+	upper, lower := math.Max(bgTone1, bgTone2), math.Min(bgTone1, bgTone2)
+	if RatioOfTones(upper, answer) >= c.contrast && RatioOfTones(lower, answer) >= c.contrast {
+		return answer
+	}
+
+	lightOption := LighterTone(upper, c.contrast)
+	darkOption := DarkerTone(lower, c.contrast)
+	if tonePrefersLightForeground(bgTone1) || tonePrefersLightForeground(bgTone2) {
+		if lightOption < 0 {
+			return 100
+		}
+		return lightOption
+	}
+	if lightOption >= 0 && darkOption < 0 {
+		return lightOption
+	}
+	if darkOption < 0 {
+		return 0
+	}
+	return darkOption
+}
+
+// pairTone resolves the tone of a role in a toneDeltaPair
+func (c *dynamicColor) pairTone(s *Scheme) float64 {
+	pair := c.pair(s)
+	bgTone := c.background(s).getTone(s)
+
+	aIsNearer := pair.polarity == polarityNearer || (pair.polarity == polarityLighter && !s.IsDark)
+	nearer, farther := pair.a, pair.b
+	if !aIsNearer {
+		nearer, farther = pair.b, pair.a
+	}
+	expansionDir := -1.0
+	if s.IsDark {
+		expansionDir = 1.0
+	}
+
+	// Each role keeps its tone if it already has enough contrast
+	nTone := nearer.tone(s)
+	if RatioOfTones(bgTone, nTone) < nearer.contrast {
+		nTone = foregroundTone(bgTone, nearer.contrast)
+	}
+	fTone := farther.tone(s)
+	if RatioOfTones(bgTone, fTone) < farther.contrast {
+		fTone = foregroundTone(bgTone, farther.contrast)
+	}
+
+	// Push the farther role away, then pull the nearer one back if that wasn't enough
+	if (fTone-nTone)*expansionDir < pair.delta {
+		fTone = clampFloat(0, 100, nTone+pair.delta*expansionDir)
+		if (fTone-nTone)*expansionDir < pair.delta {
+			nTone = clampFloat(0, 100, fTone-pair.delta*expansionDir)
+		}
+	}
+
+	// Avoid tones 50-59
+	if (50 <= nTone && nTone < 60) || (pair.stayTogether && 50 <= fTone && fTone < 60) {
+		if expansionDir > 0 {
+			nTone = 60
+			fTone = math.Max(fTone, nTone+pair.delta*expansionDir)
+		} else {
+			nTone = 49
+			fTone = math.Min(fTone, nTone+pair.delta*expansionDir)
+		}
+	} else if 50 <= fTone && fTone < 60 {
+		if expansionDir > 0 {
+			fTone = 60
+		} else {
+			fTone = 49
+		}
+	}
+
+	if c == nearer {
+		return nTone
+	}
+	return fTone
+}
+
+// findDesiredChromaByTone walks the tone away from the starting one until the palette
+// hue reaches the requested chroma, or stops gaining chroma
+func findDesiredChromaByTone(hue, chroma, tone float64, byDecreasingTone bool) float64 {
+	answer := tone
+	closest := NewHct(hue, chroma, tone)
+	if closest.Chroma() >= chroma {
+		return answer
+	}
+
+	step := 1.0
+	if byDecreasingTone {
+		step = -1.0
+	}
+	chromaPeak := closest.Chroma()
+	for closest.Chroma() < chroma {
+		answer += step
+		potential := NewHct(hue, chroma, answer)
+		if chromaPeak > potential.Chroma() {
+			break
+		}
+		if math.Abs(potential.Chroma()-chroma) < 0.4 {
+			break
+		}
+		if math.Abs(potential.Chroma()-chroma) < math.Abs(closest.Chroma()-chroma) {
+			closest = potential
+		}
+		chromaPeak = math.Max(chromaPeak, potential.Chroma())
+	}
+	return answer
+}
@@ -0,0 +1,316 @@
+package material
+
+import "math"
+
+// Hct is a color in the Hue, Chroma, Tone space Material You palettes are built in.
+// Hue and chroma come from CAM16, tone is L* from L*a*b*.
+type Hct struct {
+	hue    float64
+	chroma float64
+	tone   float64
+	argb   uint32
+}
+
+// HctFromArgb converts an ARGB color to HCT
+func HctFromArgb(argb uint32) Hct {
+	cam := cam16FromArgb(argb)
+	return Hct{hue: cam.hue, chroma: cam.chroma, tone: LstarFromArgb(argb), argb: argb}
+}
+
+// NewHct returns the sRGB color closest to the requested hue, chroma and tone.
+// Tone is preserved exactly; chroma is reduced when the request is out of gamut.
+func NewHct(hue, chroma, tone float64) Hct {
+	return HctFromArgb(solveToArgb(hue, chroma, tone))
+}
+
+// Hue returns the CAM16 hue in degrees
+func (h Hct) Hue() float64 { return h.hue }
+
+// Chroma returns the CAM16 chroma
+func (h Hct) Chroma() float64 { return h.chroma }
+
+// Tone returns the L* tone (0 black, 100 white)
+func (h Hct) Tone() float64 { return h.tone }
+
+// Argb returns the color as 0xAARRGGBB
+func (h Hct) Argb() uint32 { return h.argb }
+
+// WithTone returns the color with the same hue and chroma at another tone
+func (h Hct) WithTone(tone float64) Hct {
+	return NewHct(h.hue, h.chroma, tone)
+}
+
+// scaledDiscountFromLinrgb takes linear RGB (0-100) to CAM16's cone responses, already
+// discounted by the default viewing conditions. linrgbFromScaledDiscount is its inverse.
+var scaledDiscountFromLinrgb = [3][3]float64{
+	{0.001200833568784504, 0.002389694492170889, 0.0002795742885861124},
+	{0.0005891086651375999, 0.0029785502573438758, 0.0003270666104008398},
+	{0.00010146692491640572, 0.0005364214359186694, 0.0032979401770712076},
+}
+
+var linrgbFromScaledDiscount = [3][3]float64{
+	{1373.2198709594231, -1100.4251190754821, -7.278681089101213},
+	{-271.815969077903, 559.6580465940733, -32.46047482791194},
+	{1.9622899599665666, -57.173814538844006, 308.7233197812385},
+}
+
+var yFromLinrgb = [3]float64{0.2126, 0.7152, 0.0722}
+
+// criticalPlanes are the linear RGB values halfway between consecutive 8-bit sRGB values
+var criticalPlanes = func() [255]float64 {
+	var planes [255]float64
+	for i := range planes {
+		normalized := (float64(i) + 0.5) / 255.0
+		if normalized <= 0.040449936 {
+			planes[i] = normalized / 12.92 * 100.0
+		} else {
+			planes[i] = math.Pow((normalized+0.055)/1.055, 2.4) * 100.0
+		}
+	}
+	return planes
+}()
+
+// solveToArgb returns the sRGB color with the given hue and tone and the chroma closest to
+// the requested one. This is the HctSolver of material-color-utilities: the exact answer is
+// found with Newton's method on CAM16 lightness J, and when the color is out of gamut the
+// surface of the sRGB cube is bisected for the hue at that tone.
+func solveToArgb(hue, chroma, tone float64) uint32 {
+	if chroma < 0.0001 || tone < 0.0001 || tone > 99.9999 {
+		return ArgbFromLstar(tone)
+	}
+	hueRadians := sanitizeDegrees(hue) / 180.0 * math.Pi
+	y := YFromLstar(tone)
+	if exact := findResultByJ(hueRadians, chroma, y); exact != 0 {
+		return exact
+	}
+	return argbFromLinrgb(bisectToLimit(y, hueRadians))
+}
+
+// findResultByJ solves for the color with the given hue, chroma and luminance Y, returning
+// 0 when it is outside the sRGB gamut
+func findResultByJ(hueRadians, chroma, y float64) uint32 {
+	vc := defaultViewingConditions
+	// Initial estimate of J
+	j := math.Sqrt(y) * 11.0
+
+	tInnerCoeff := 1 / math.Pow(1.64-math.Pow(0.29, vc.n), 0.73)
+	eHue := 0.25 * (math.Cos(hueRadians+2.0) + 3.8)
+	p1 := eHue * (50000.0 / 13.0) * vc.nc * vc.ncb
+	hSin := math.Sin(hueRadians)
+	hCos := math.Cos(hueRadians)
+
+	for round := 0; round < 5; round++ {
+		jNormalized := j / 100.0
+		alpha := 0.0
+		if chroma != 0 && j != 0 {
+			alpha = chroma / math.Sqrt(jNormalized)
+		}
+		t := math.Pow(alpha*tInnerCoeff, 1.0/0.9)
+		ac := vc.aw * math.Pow(jNormalized, 1.0/vc.c/vc.z)
+		p2 := ac / vc.nbb
+		gamma := 23.0 * (p2 + 0.305) * t / (23.0*p1 + 11*t*hCos + 108.0*t*hSin)
+		a := gamma * hCos
+		b := gamma * hSin
+		rA := (460.0*p2 + 451.0*a + 288.0*b) / 1403.0
+		gA := (460.0*p2 - 891.0*a - 261.0*b) / 1403.0
+		bA := (460.0*p2 - 220.0*a - 6300.0*b) / 1403.0
+		linrgb := matrixMultiply([3]float64{
+			inverseChromaticAdaptation(rA),
+			inverseChromaticAdaptation(gA),
+			inverseChromaticAdaptation(bA),
+		}, linrgbFromScaledDiscount)
+
+		if linrgb[0] < 0 || linrgb[1] < 0 || linrgb[2] < 0 {
+			return 0
+		}
+		fnj := yFromLinrgb[0]*linrgb[0] + yFromLinrgb[1]*linrgb[1] + yFromLinrgb[2]*linrgb[2]
+		if fnj <= 0 {
+			return 0
+		}
+		if round == 4 || math.Abs(fnj-y) < 0.002 {
+			if linrgb[0] > 100.01 || linrgb[1] > 100.01 || linrgb[2] > 100.01 {
+				return 0
+			}
+			return argbFromLinrgb(linrgb)
+		}
+		// Newton's method, with 2 * fn(j) / j as the derivative
+		j = j - (fnj-y)*j/(2*fnj)
+	}
+	return 0
+}
+
+// bisectToLimit finds the most chromatic in-gamut linear RGB color with the given
+// luminance Y and hue, refining the cube edge segment down to 8-bit precision
+func bisectToLimit(y, targetHue float64) [3]float64 {
+	left, right := bisectToSegment(y, targetHue)
+	leftHue := hueOf(left)
+	for axis := 0; axis < 3; axis++ {
+		if left[axis] == right[axis] {
+			continue
+		}
+		var lPlane, rPlane int
+		if left[axis] < right[axis] {
+			lPlane = criticalPlaneBelow(trueDelinearized(left[axis]))
+			rPlane = criticalPlaneAbove(trueDelinearized(right[axis]))
+		} else {
+			lPlane = criticalPlaneAbove(trueDelinearized(left[axis]))
+			rPlane = criticalPlaneBelow(trueDelinearized(right[axis]))
+		}
+		for i := 0; i < 8; i++ {
+			if abs(rPlane-lPlane) <= 1 {
+				break
+			}
+			mPlane := int(math.Floor(float64(lPlane+rPlane) / 2.0))
+			mid := setCoordinate(left, criticalPlanes[mPlane], right, axis)
+			midHue := hueOf(mid)
+			if areInCyclicOrder(leftHue, targetHue, midHue) {
+				right = mid
+				rPlane = mPlane
+			} else {
+				left = mid
+				leftHue = midHue
+				lPlane = mPlane
+			}
+		}
+	}
+	return [3]float64{(left[0] + right[0]) / 2, (left[1] + right[1]) / 2, (left[2] + right[2]) / 2}
+}
+
+// bisectToSegment finds the edge of the RGB cube, on the plane of constant Y, that
+// contains the target hue
+func bisectToSegment(y, targetHue float64) (left, right [3]float64) {
+	left = [3]float64{-1, -1, -1}
+	right = left
+	leftHue, rightHue := 0.0, 0.0
+	initialized := false
+	uncut := true
+	for n := 0; n < 12; n++ {
+		mid, ok := nthVertex(y, n)
+		if !ok {
+			continue
+		}
+		midHue := hueOf(mid)
+		if !initialized {
+			left, right = mid, mid
+			leftHue, rightHue = midHue, midHue
+			initialized = true
+			continue
+		}
+		if uncut || areInCyclicOrder(leftHue, midHue, rightHue) {
+			uncut = false
+			if areInCyclicOrder(leftHue, targetHue, midHue) {
+				right = mid
+				rightHue = midHue
+			} else {
+				left = mid
+				leftHue = midHue
+			}
+		}
+	}
+	return left, right
+}
+
+// nthVertex returns the nth of the 12 points where the plane of constant Y can cross an
+// edge of the RGB cube, reporting false when the crossing is outside the cube
+func nthVertex(y float64, n int) ([3]float64, bool) {
+	kR, kG, kB := yFromLinrgb[0], yFromLinrgb[1], yFromLinrgb[2]
+	coordA := 0.0
+	if n%4 > 1 {
+		coordA = 100.0
+	}
+	coordB := 0.0
+	if n%2 != 0 {
+		coordB = 100.0
+	}
+	var v [3]float64
+	var free float64
+	switch {
+	case n < 4:
+		g, b := coordA, coordB
+		free = (y - g*kG - b*kB) / kR
+		v = [3]float64{free, g, b}
+	case n < 8:
+		b, r := coordA, coordB
+		free = (y - r*kR - b*kB) / kG
+		v = [3]float64{r, free, b}
+	default:
+		r, g := coordA, coordB
+		free = (y - r*kR - g*kG) / kB
+		v = [3]float64{r, g, free}
+	}
+	return v, 0.0 <= free && free <= 100.0
+}
+
+// hueOf returns the CAM16 hue, in radians, of a linear RGB color
+func hueOf(linrgb [3]float64) float64 {
+	scaledDiscount := matrixMultiply(linrgb, scaledDiscountFromLinrgb)
+	rA := chromaticAdaptation(scaledDiscount[0])
+	gA := chromaticAdaptation(scaledDiscount[1])
+	bA := chromaticAdaptation(scaledDiscount[2])
+	a := (11.0*rA + -12.0*gA + bA) / 11.0 // Redness-greenness
+	b := (rA + gA - 2.0*bA) / 9.0         // Yellowness-blueness
+	return math.Atan2(b, a)
+}
+
+func chromaticAdaptation(component float64) float64 {
+	af := math.Pow(math.Abs(component), 0.42)
+	return signum(component) * 400.0 * af / (af + 27.13)
+}
+
+func inverseChromaticAdaptation(adapted float64) float64 {
+	adaptedAbs := math.Abs(adapted)
+	base := math.Max(0, 27.13*adaptedAbs/(400.0-adaptedAbs))
+	return signum(adapted) * math.Pow(base, 1.0/0.42)
+}
+
+// areInCyclicOrder reports whether going counterclockwise from a, b comes before c
+func areInCyclicOrder(a, b, c float64) bool {
+	return sanitizeRadians(b-a) < sanitizeRadians(c-a)
+}
+
+func sanitizeRadians(angle float64) float64 {
+	return math.Mod(angle+math.Pi*8, math.Pi*2)
+}
+
+// setCoordinate returns the point on the segment from source to target whose axis
+// coordinate is the given value
+func setCoordinate(source [3]float64, coordinate float64, target [3]float64, axis int) [3]float64 {
+	t := (coordinate - source[axis]) / (target[axis] - source[axis])
+	return [3]float64{
+		source[0] + (target[0]-source[0])*t,
+		source[1] + (target[1]-source[1])*t,
+		source[2] + (target[2]-source[2])*t,
+	}
+}
+
+// trueDelinearized converts a linear RGB component (0-100) to an unrounded 8-bit sRGB value
+func trueDelinearized(component float64) float64 {
+	normalized := component / 100.0
+	if normalized <= 0.0031308 {
+		return normalized * 12.92 * 255.0
+	}
+	return (1.055*math.Pow(normalized, 1.0/2.4) - 0.055) * 255.0
+}
+
+func criticalPlaneBelow(x float64) int { return int(math.Floor(x - 0.5)) }
+
+func criticalPlaneAbove(x float64) int { return int(math.Ceil(x - 0.5)) }
+
+func argbFromLinrgb(linrgb [3]float64) uint32 {
+	return ArgbFromRgb(delinearized(linrgb[0]), delinearized(linrgb[1]), delinearized(linrgb[2]))
+}
+
+func matrixMultiply(row [3]float64, matrix [3][3]float64) [3]float64 {
+	return [3]float64{
+		row[0]*matrix[0][0] + row[1]*matrix[0][1] + row[2]*matrix[0][2],
+		row[0]*matrix[1][0] + row[1]*matrix[1][1] + row[2]*matrix[1][2],
+		row[0]*matrix[2][0] + row[1]*matrix[2][1] + row[2]*matrix[2][2],
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
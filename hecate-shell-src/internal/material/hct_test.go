@@ -0,0 +1,55 @@
+package material
+
+import (
+	"math"
+	"testing"
+)
+
+// Reference values from material-color-utilities' HCT tests
+func TestHctFromArgb(t *testing.T) {
+	tests := []struct {
+		argb              uint32
+		hue, chroma, tone float64
+	}{
+		{0xff0000ff, 282.788, 87.230, 32.302},
+		{0xffff0000, 27.408, 113.357, 53.233},
+		{0xff00ff00, 142.139, 108.410, 87.737},
+		{0xffffffff, 209.492, 2.869, 100.0},
+		{0xff000000, 0.0, 0.0, 0.0},
+	}
+	for _, tt := range tests {
+		hct := HctFromArgb(tt.argb)
+		if math.Abs(hct.Hue()-tt.hue) > 0.01 || math.Abs(hct.Chroma()-tt.chroma) > 0.01 || math.Abs(hct.Tone()-tt.tone) > 0.01 {
+			t.Errorf("HctFromArgb(%08x) = %.3f/%.3f/%.3f, want %.3f/%.3f/%.3f",
+				tt.argb, hct.Hue(), hct.Chroma(), hct.Tone(), tt.hue, tt.chroma, tt.tone)
+		}
+	}
+}
+
+func TestNewHctRoundTrip(t *testing.T) {
+	for _, argb := range []uint32{0xff0000ff, 0xffff0000, 0xff00ff00, 0xff4285f4, 0xff6750a4, 0xffb3261e, 0xff123456} {
+		hct := HctFromArgb(argb)
+		if got := NewHct(hct.Hue(), hct.Chroma(), hct.Tone()).Argb(); got != argb {
+			t.Errorf("NewHct(HctFromArgb(%08x)) = %08x", argb, got)
+		}
+	}
+}
+
+func TestNewHctPreservesTone(t *testing.T) {
+	for hue := 15.0; hue < 360; hue += 30 {
+		for chroma := 0.0; chroma <= 100; chroma += 10 {
+			for tone := 20.0; tone <= 80; tone += 10 {
+				hct := NewHct(hue, chroma, tone)
+				if math.Abs(hct.Tone()-tone) > 0.5 {
+					t.Errorf("NewHct(%v, %v, %v) has tone %.3f", hue, chroma, tone, hct.Tone())
+				}
+				if hct.Chroma() > chroma+2.5 {
+					t.Errorf("NewHct(%v, %v, %v) has chroma %.3f", hue, chroma, tone, hct.Chroma())
+				}
+				if hct.Chroma() > 2.5 && differenceDegrees(hct.Hue(), hue) > 4 {
+					t.Errorf("NewHct(%v, %v, %v) has hue %.3f", hue, chroma, tone, hct.Hue())
+				}
+			}
+		}
+	}
+}
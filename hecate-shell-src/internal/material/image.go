@@ -0,0 +1,81 @@
+package material
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+
+	_ "golang.org/x/image/webp"
+)
+
+// maxSampleSize bounds the image side length used for quantization.
+// Large wallpapers are sampled down; color statistics barely change.
+const maxSampleSize = 128
+
+// maxQuantizedColors is the number of clusters the image is reduced to before scoring
+const maxQuantizedColors = 128
+
+// LoadImage decodes a JPEG, PNG, GIF or WebP file
+func LoadImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// ImagePixels samples the opaque pixels of an image on an even grid
+func ImagePixels(img image.Image) []uint32 {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	step := 1
+	if longest := max(width, height); longest > maxSampleSize {
+		step = (longest + maxSampleSize - 1) / maxSampleSize
+	}
+
+	pixels := make([]uint32, 0, (width/step+1)*(height/step+1))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0xffff {
+				continue
+			}
+			pixels = append(pixels, ArgbFromRgb(int(r>>8), int(g>>8), int(b>>8)))
+		}
+	}
+	return pixels
+}
+
+// SourceColorsFromImage returns the best theme source colors in an image, best first
+func SourceColorsFromImage(img image.Image) []uint32 {
+	return Score(QuantizeCelebi(ImagePixels(img), maxQuantizedColors), 4)
+}
+
+// SourceColorFromImage returns the single best theme source color in an image
+func SourceColorFromImage(img image.Image) uint32 {
+	return SourceColorsFromImage(img)[0]
+}
+
+// AverageTone returns the L* (tone) of an image's mean luminance
+func AverageTone(img image.Image) float64 {
+	pixels := ImagePixels(img)
+	if len(pixels) == 0 {
+		return 50.0
+	}
+
+	sum := 0.0
+	for _, pixel := range pixels {
+		sum += XyzFromArgb(pixel)[1]
+	}
+	return LstarFromY(sum / float64(len(pixels)))
+}
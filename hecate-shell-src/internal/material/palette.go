@@ -0,0 +1,58 @@
+package material
+
+import "math"
+
+// TonalPalette is a hue and chroma pair that can produce a color at any tone
+type TonalPalette struct {
+	hue    float64
+	chroma float64
+	cache  map[int]uint32
+}
+
+// NewTonalPalette creates a palette from a hue and chroma
+func NewTonalPalette(hue, chroma float64) *TonalPalette {
+	return &TonalPalette{hue: hue, chroma: chroma, cache: make(map[int]uint32)}
+}
+
+// TonalPaletteFromHct creates a palette from the hue and chroma of a color
+func TonalPaletteFromHct(hct Hct) *TonalPalette {
+	return NewTonalPalette(hct.Hue(), hct.Chroma())
+}
+
+// Hue returns the palette hue
+func (p *TonalPalette) Hue() float64 { return p.hue }
+
+// Chroma returns the palette chroma
+func (p *TonalPalette) Chroma() float64 { return p.chroma }
+
+// Tone returns the palette color at the given tone (0-100)
+func (p *TonalPalette) Tone(tone int) uint32 {
+	if argb, ok := p.cache[tone]; ok {
+		return argb
+	}
+	argb := NewHct(p.hue, p.chroma, float64(tone)).Argb()
+	p.cache[tone] = argb
+	return argb
+}
+
+// ToneHct returns the palette color at a fractional tone
+func (p *TonalPalette) ToneHct(tone float64) Hct {
+	return NewHct(p.hue, p.chroma, tone)
+}
+
+// isDisliked reports whether a color falls in the dark yellow-green range that
+// is universally disliked (bile, mud, vomit).
+func isDisliked(hct Hct) bool {
+	huePasses := math.Round(hct.Hue()) >= 90.0 && math.Round(hct.Hue()) <= 111.0
+	chromaPasses := math.Round(hct.Chroma()) > 16.0
+	tonePasses := math.Round(hct.Tone()) < 65.0
+	return huePasses && chromaPasses && tonePasses
+}
+
+// fixIfDisliked lightens disliked colors so they read as a pleasant olive instead
+func fixIfDisliked(hct Hct) Hct {
+	if isDisliked(hct) {
+		return NewHct(hct.Hue(), hct.Chroma(), 70.0)
+	}
+	return hct
+}
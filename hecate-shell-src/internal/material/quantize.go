@@ -0,0 +1,460 @@
+package material
+
+import (
+	"math"
+	"sort"
+)
+
+// QuantizeCelebi reduces pixels to at most maxColors representative colors and their
+// pixel counts. Wu's quantizer picks the starting clusters, which weighted k-means
+// then refines in L*a*b*.
+func QuantizeCelebi(pixels []uint32, maxColors int) map[uint32]int {
+	wu := newWuQuantizer()
+	starting := wu.quantize(pixels, maxColors)
+	return quantizeWsmeans(pixels, starting, maxColors)
+}
+
+// Wu's color quantizer (Graphics Gems II), operating on a 5-bit RGB histogram.
+
+const (
+	wuIndexBits  = 5
+	wuIndexCount = 33 // (1 << wuIndexBits) + 1
+	wuTotalSize  = wuIndexCount * wuIndexCount * wuIndexCount
+)
+
+type wuBox struct {
+	r0, r1, g0, g1, b0, b1 int
+	vol                    int
+}
+
+type wuDirection int
+
+const (
+	wuRed wuDirection = iota
+	wuGreen
+	wuBlue
+)
+
+type wuQuantizer struct {
+	weights  []float64
+	momentsR []float64
+	momentsG []float64
+	momentsB []float64
+	moments  []float64
+	cubes    []wuBox
+}
+
+func newWuQuantizer() *wuQuantizer {
+	return &wuQuantizer{}
+}
+
+func wuIndex(r, g, b int) int {
+	return r*wuIndexCount*wuIndexCount + g*wuIndexCount + b
+}
+
+func (w *wuQuantizer) quantize(pixels []uint32, maxColors int) []uint32 {
+	w.constructHistogram(pixels)
+	w.createMoments()
+	count := w.createBoxes(maxColors)
+	return w.createResult(count)
+}
+
+func (w *wuQuantizer) constructHistogram(pixels []uint32) {
+	w.weights = make([]float64, wuTotalSize)
+	w.momentsR = make([]float64, wuTotalSize)
+	w.momentsG = make([]float64, wuTotalSize)
+	w.momentsB = make([]float64, wuTotalSize)
+	w.moments = make([]float64, wuTotalSize)
+
+	counts := make(map[uint32]int)
+	for _, pixel := range pixels {
+		counts[pixel]++
+	}
+
+	const bitsToRemove = 8 - wuIndexBits
+	for pixel, count := range counts {
+		red := RedFromArgb(pixel)
+		green := GreenFromArgb(pixel)
+		blue := BlueFromArgb(pixel)
+		index := wuIndex((red>>bitsToRemove)+1, (green>>bitsToRemove)+1, (blue>>bitsToRemove)+1)
+
+		c := float64(count)
+		w.weights[index] += c
+		w.momentsR[index] += float64(red) * c
+		w.momentsG[index] += float64(green) * c
+		w.momentsB[index] += float64(blue) * c
+		w.moments[index] += c * float64(red*red+green*green+blue*blue)
+	}
+}
+
+// createMoments turns the histogram into cumulative moments so any box can be summed in O(1)
+func (w *wuQuantizer) createMoments() {
+	for r := 1; r < wuIndexCount; r++ {
+		var area, areaR, areaG, areaB, area2 [wuIndexCount]float64
+
+		for g := 1; g < wuIndexCount; g++ {
+			var line, lineR, lineG, lineB, line2 float64
+
+			for b := 1; b < wuIndexCount; b++ {
+				index := wuIndex(r, g, b)
+				line += w.weights[index]
+				lineR += w.momentsR[index]
+				lineG += w.momentsG[index]
+				lineB += w.momentsB[index]
+				line2 += w.moments[index]
+
+				area[b] += line
+				areaR[b] += lineR
+				areaG[b] += lineG
+				areaB[b] += lineB
+				area2[b] += line2
+
+				previous := wuIndex(r-1, g, b)
+				w.weights[index] = w.weights[previous] + area[b]
+				w.momentsR[index] = w.momentsR[previous] + areaR[b]
+				w.momentsG[index] = w.momentsG[previous] + areaG[b]
+				w.momentsB[index] = w.momentsB[previous] + areaB[b]
+				w.moments[index] = w.moments[previous] + area2[b]
+			}
+		}
+	}
+}
+
+func (w *wuQuantizer) createBoxes(maxColors int) int {
+	w.cubes = make([]wuBox, maxColors)
+	w.cubes[0] = wuBox{r1: wuIndexCount - 1, g1: wuIndexCount - 1, b1: wuIndexCount - 1}
+
+	volumeVariance := make([]float64, maxColors)
+	next := 0
+	generated := maxColors
+	for i := 1; i < maxColors; i++ {
+		if w.cut(&w.cubes[next], &w.cubes[i]) {
+			volumeVariance[next] = 0
+			if w.cubes[next].vol > 1 {
+				volumeVariance[next] = w.variance(w.cubes[next])
+			}
+			volumeVariance[i] = 0
+			if w.cubes[i].vol > 1 {
+				volumeVariance[i] = w.variance(w.cubes[i])
+			}
+		} else {
+			volumeVariance[next] = 0
+			i--
+		}
+
+		next = 0
+		temp := volumeVariance[0]
+		for j := 1; j <= i; j++ {
+			if volumeVariance[j] > temp {
+				temp = volumeVariance[j]
+				next = j
+			}
+		}
+		if temp <= 0.0 {
+			generated = i + 1
+			break
+		}
+	}
+
+	return generated
+}
+
+func (w *wuQuantizer) createResult(count int) []uint32 {
+	var colors []uint32
+	for i := 0; i < count; i++ {
+		cube := w.cubes[i]
+		weight := w.volume(cube, w.weights)
+		if weight > 0 {
+			r := int(w.volume(cube, w.momentsR) / weight)
+			g := int(w.volume(cube, w.momentsG) / weight)
+			b := int(w.volume(cube, w.momentsB) / weight)
+			colors = append(colors, ArgbFromRgb(r, g, b))
+		}
+	}
+	return colors
+}
+
+func (w *wuQuantizer) variance(cube wuBox) float64 {
+	dr := w.volume(cube, w.momentsR)
+	dg := w.volume(cube, w.momentsG)
+	db := w.volume(cube, w.momentsB)
+	xx := w.volume(cube, w.moments)
+	hypotenuse := dr*dr + dg*dg + db*db
+	return xx - hypotenuse/w.volume(cube, w.weights)
+}
+
+func (w *wuQuantizer) cut(one, two *wuBox) bool {
+	wholeR := w.volume(*one, w.momentsR)
+	wholeG := w.volume(*one, w.momentsG)
+	wholeB := w.volume(*one, w.momentsB)
+	wholeW := w.volume(*one, w.weights)
+
+	cutR, maxR := w.maximize(*one, wuRed, one.r0+1, one.r1, wholeR, wholeG, wholeB, wholeW)
+	cutG, maxG := w.maximize(*one, wuGreen, one.g0+1, one.g1, wholeR, wholeG, wholeB, wholeW)
+	cutB, maxB := w.maximize(*one, wuBlue, one.b0+1, one.b1, wholeR, wholeG, wholeB, wholeW)
+
+	var direction wuDirection
+	switch {
+	case maxR >= maxG && maxR >= maxB:
+		if cutR < 0 {
+			return false
+		}
+		direction = wuRed
+	case maxG >= maxR && maxG >= maxB:
+		direction = wuGreen
+	default:
+		direction = wuBlue
+	}
+
+	two.r1 = one.r1
+	two.g1 = one.g1
+	two.b1 = one.b1
+
+	switch direction {
+	case wuRed:
+		one.r1 = cutR
+		two.r0, two.g0, two.b0 = one.r1, one.g0, one.b0
+	case wuGreen:
+		one.g1 = cutG
+		two.r0, two.g0, two.b0 = one.r0, one.g1, one.b0
+	case wuBlue:
+		one.b1 = cutB
+		two.r0, two.g0, two.b0 = one.r0, one.g0, one.b1
+	}
+
+	one.vol = (one.r1 - one.r0) * (one.g1 - one.g0) * (one.b1 - one.b0)
+	two.vol = (two.r1 - two.r0) * (two.g1 - two.g0) * (two.b1 - two.b0)
+	return true
+}
+
+func (w *wuQuantizer) maximize(cube wuBox, direction wuDirection, first, last int, wholeR, wholeG, wholeB, wholeW float64) (int, float64) {
+	bottomR := w.bottom(cube, direction, w.momentsR)
+	bottomG := w.bottom(cube, direction, w.momentsG)
+	bottomB := w.bottom(cube, direction, w.momentsB)
+	bottomW := w.bottom(cube, direction, w.weights)
+
+	max := 0.0
+	cut := -1
+	for i := first; i < last; i++ {
+		halfR := bottomR + w.top(cube, direction, i, w.momentsR)
+		halfG := bottomG + w.top(cube, direction, i, w.momentsG)
+		halfB := bottomB + w.top(cube, direction, i, w.momentsB)
+		halfW := bottomW + w.top(cube, direction, i, w.weights)
+		if halfW == 0 {
+			continue
+		}
+
+		temp := (halfR*halfR + halfG*halfG + halfB*halfB) / halfW
+
+		halfR = wholeR - halfR
+		halfG = wholeG - halfG
+		halfB = wholeB - halfB
+		halfW = wholeW - halfW
+		if halfW == 0 {
+			continue
+		}
+
+		temp += (halfR*halfR + halfG*halfG + halfB*halfB) / halfW
+		if temp > max {
+			max = temp
+			cut = i
+		}
+	}
+
+	return cut, max
+}
+
+func (w *wuQuantizer) volume(cube wuBox, moment []float64) float64 {
+	return moment[wuIndex(cube.r1, cube.g1, cube.b1)] -
+		moment[wuIndex(cube.r1, cube.g1, cube.b0)] -
+		moment[wuIndex(cube.r1, cube.g0, cube.b1)] +
+		moment[wuIndex(cube.r1, cube.g0, cube.b0)] -
+		moment[wuIndex(cube.r0, cube.g1, cube.b1)] +
+		moment[wuIndex(cube.r0, cube.g1, cube.b0)] +
+		moment[wuIndex(cube.r0, cube.g0, cube.b1)] -
+		moment[wuIndex(cube.r0, cube.g0, cube.b0)]
+}
+
+func (w *wuQuantizer) bottom(cube wuBox, direction wuDirection, moment []float64) float64 {
+	switch direction {
+	case wuRed:
+		return -moment[wuIndex(cube.r0, cube.g1, cube.b1)] +
+			moment[wuIndex(cube.r0, cube.g1, cube.b0)] +
+			moment[wuIndex(cube.r0, cube.g0, cube.b1)] -
+			moment[wuIndex(cube.r0, cube.g0, cube.b0)]
+	case wuGreen:
+		return -moment[wuIndex(cube.r1, cube.g0, cube.b1)] +
+			moment[wuIndex(cube.r1, cube.g0, cube.b0)] +
+			moment[wuIndex(cube.r0, cube.g0, cube.b1)] -
+			moment[wuIndex(cube.r0, cube.g0, cube.b0)]
+	default:
+		return -moment[wuIndex(cube.r1, cube.g1, cube.b0)] +
+			moment[wuIndex(cube.r1, cube.g0, cube.b0)] +
+			moment[wuIndex(cube.r0, cube.g1, cube.b0)] -
+			moment[wuIndex(cube.r0, cube.g0, cube.b0)]
+	}
+}
+
+func (w *wuQuantizer) top(cube wuBox, direction wuDirection, position int, moment []float64) float64 {
+	switch direction {
+	case wuRed:
+		return moment[wuIndex(position, cube.g1, cube.b1)] -
+			moment[wuIndex(position, cube.g1, cube.b0)] -
+			moment[wuIndex(position, cube.g0, cube.b1)] +
+			moment[wuIndex(position, cube.g0, cube.b0)]
+	case wuGreen:
+		return moment[wuIndex(cube.r1, position, cube.b1)] -
+			moment[wuIndex(cube.r1, position, cube.b0)] -
+			moment[wuIndex(cube.r0, position, cube.b1)] +
+			moment[wuIndex(cube.r0, position, cube.b0)]
+	default:
+		return moment[wuIndex(cube.r1, cube.g1, position)] -
+			moment[wuIndex(cube.r1, cube.g0, position)] -
+			moment[wuIndex(cube.r0, cube.g1, position)] +
+			moment[wuIndex(cube.r0, cube.g0, position)]
+	}
+}
+
+// Weighted k-means in L*a*b*, seeded with the Wu clusters.
+
+const (
+	wsmeansMaxIterations       = 10
+	wsmeansMinMovementDistance = 3.0
+)
+
+func quantizeWsmeans(pixels []uint32, startingClusters []uint32, maxColors int) map[uint32]int {
+	pixelToCount := make(map[uint32]int)
+	var uniquePixels []uint32
+	for _, pixel := range pixels {
+		if _, ok := pixelToCount[pixel]; !ok {
+			uniquePixels = append(uniquePixels, pixel)
+		}
+		pixelToCount[pixel]++
+	}
+
+	points := make([][3]float64, len(uniquePixels))
+	counts := make([]float64, len(uniquePixels))
+	for i, pixel := range uniquePixels {
+		points[i] = LabFromArgb(pixel)
+		counts[i] = float64(pixelToCount[pixel])
+	}
+
+	clusterCount := maxColors
+	if len(points) < clusterCount {
+		clusterCount = len(points)
+	}
+	if len(startingClusters) > 0 && len(startingClusters) < clusterCount {
+		clusterCount = len(startingClusters)
+	}
+	if clusterCount == 0 {
+		return map[uint32]int{}
+	}
+
+	clusters := make([][3]float64, clusterCount)
+	for i := 0; i < clusterCount; i++ {
+		if i < len(startingClusters) {
+			clusters[i] = LabFromArgb(startingClusters[i])
+		} else {
+			clusters[i] = points[i*len(points)/clusterCount]
+		}
+	}
+
+	// Start every point on its nearest cluster; only meaningful moves count afterwards
+	clusterIndices := make([]int, len(points))
+	for i, point := range points {
+		clusterIndices[i] = nearestCluster(point, clusters)
+	}
+
+	pixelCountSums := make([]float64, clusterCount)
+	for iteration := 0; iteration < wsmeansMaxIterations; iteration++ {
+		pointsMoved := 0
+		if iteration > 0 {
+			for i, point := range points {
+				previous := clusterIndices[i]
+				previousDistance := labDistance(point, clusters[previous])
+				candidate := nearestCluster(point, clusters)
+				candidateDistance := labDistance(point, clusters[candidate])
+				if candidate != previous && sqrtDiff(candidateDistance, previousDistance) > wsmeansMinMovementDistance {
+					pointsMoved++
+					clusterIndices[i] = candidate
+				}
+			}
+			if pointsMoved == 0 {
+				break
+			}
+		}
+
+		sums := make([][3]float64, clusterCount)
+		for i := range pixelCountSums {
+			pixelCountSums[i] = 0
+		}
+		for i, point := range points {
+			index := clusterIndices[i]
+			count := counts[i]
+			pixelCountSums[index] += count
+			sums[index][0] += point[0] * count
+			sums[index][1] += point[1] * count
+			sums[index][2] += point[2] * count
+		}
+		for i := range clusters {
+			if pixelCountSums[i] == 0 {
+				clusters[i] = [3]float64{}
+				continue
+			}
+			clusters[i] = [3]float64{
+				sums[i][0] / pixelCountSums[i],
+				sums[i][1] / pixelCountSums[i],
+				sums[i][2] / pixelCountSums[i],
+			}
+		}
+	}
+
+	result := make(map[uint32]int)
+	for i, cluster := range clusters {
+		count := int(pixelCountSums[i])
+		if count == 0 {
+			continue
+		}
+		argb := ArgbFromLab(cluster[0], cluster[1], cluster[2])
+		if _, ok := result[argb]; ok {
+			continue
+		}
+		result[argb] = count
+	}
+	return result
+}
+
+func nearestCluster(point [3]float64, clusters [][3]float64) int {
+	best := 0
+	bestDistance := labDistance(point, clusters[0])
+	for j := 1; j < len(clusters); j++ {
+		if d := labDistance(point, clusters[j]); d < bestDistance {
+			bestDistance = d
+			best = j
+		}
+	}
+	return best
+}
+
+// labDistance returns the squared euclidean distance between two L*a*b* points
+func labDistance(a, b [3]float64) float64 {
+	dl := a[0] - b[0]
+	da := a[1] - b[1]
+	db := a[2] - b[2]
+	return dl*dl + da*da + db*db
+}
+
+// sqrtDiff returns how far apart two squared distances are in linear terms
+func sqrtDiff(a, b float64) float64 {
+	return math.Abs(math.Sqrt(a) - math.Sqrt(b))
+}
+
+// sortedColors returns the keys of a color count map in a stable order
+func sortedColors(counts map[uint32]int) []uint32 {
+	colors := make([]uint32, 0, len(counts))
+	for argb := range counts {
+		colors = append(colors, argb)
+	}
+	sort.Slice(colors, func(i, j int) bool { return colors[i] < colors[j] })
+	return colors
+}
@@ -0,0 +1,42 @@
+package material
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// Reference values from material-color-utilities' Celebi quantizer tests
+func TestQuantizeCelebi(t *testing.T) {
+	const (
+		red   = 0xffff0000
+		green = 0xff00ff00
+		blue  = 0xff0000ff
+	)
+	tests := []struct {
+		name   string
+		pixels []uint32
+		want   []uint32
+	}{
+		{"1 random", []uint32{0xff141216}, []uint32{0xff141216}},
+		{"1R", []uint32{red}, []uint32{red}},
+		{"1G", []uint32{green}, []uint32{green}},
+		{"1B", []uint32{blue}, []uint32{blue}},
+		{"5B", []uint32{blue, blue, blue, blue, blue}, []uint32{blue}},
+		{"2R 3G", []uint32{red, red, green, green, green}, []uint32{red, green}},
+		{"1R 1G 1B", []uint32{red, green, blue}, []uint32{red, green, blue}},
+	}
+	for _, tt := range tests {
+		got := slices.Sorted(maps.Keys(QuantizeCelebi(tt.pixels, 128)))
+		if want := slices.Sorted(slices.Values(tt.want)); !slices.Equal(got, want) {
+			t.Errorf("%s: QuantizeCelebi = %08x, want %08x", tt.name, got, want)
+		}
+	}
+}
+
+func TestQuantizeCelebiCounts(t *testing.T) {
+	got := QuantizeCelebi([]uint32{0xffff0000, 0xffff0000, 0xff00ff00, 0xff00ff00, 0xff00ff00}, 128)
+	if got[0xffff0000] != 2 || got[0xff00ff00] != 3 {
+		t.Errorf("QuantizeCelebi counts = %v, want 2 red and 3 green", got)
+	}
+}
@@ -0,0 +1,512 @@
+package material
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Variant selects how the tonal palettes are derived from the source color.
+// Names match matugen's -t values without the "scheme-" prefix.
+type Variant string
+
+const (
+	VariantTonalSpot  Variant = "tonal-spot"  // Calm, low chroma; Android's default
+	VariantExpressive Variant = "expressive"  // Playful, hues rotated away from the source
+	VariantVibrant    Variant = "vibrant"     // Maximum chroma on the primary palette
+	VariantMonochrome Variant = "monochrome"  // Grayscale
+	VariantNeutral    Variant = "neutral"     // Nearly grayscale with a hint of the source
+	VariantRainbow    Variant = "rainbow"     // Colorful accents on a gray base
+	VariantFruitSalad Variant = "fruit-salad" // Rotated primary, playful mix
+	VariantContent    Variant = "content"     // Source color as-is, analogous tertiary
+	VariantFidelity   Variant = "fidelity"    // Source color as-is, complementary tertiary
+)
+
+// Variants lists every supported scheme variant
+var Variants = []Variant{
+	VariantTonalSpot,
+	VariantExpressive,
+	VariantVibrant,
+	VariantMonochrome,
+	VariantNeutral,
+	VariantRainbow,
+	VariantFruitSalad,
+	VariantContent,
+	VariantFidelity,
+}
+
+// ParseVariant accepts a variant name, with or without matugen's "scheme-" prefix
+func ParseVariant(name string) (Variant, error) {
+	name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "scheme-")
+	for _, v := range Variants {
+		if string(v) == name {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("unknown scheme %q", name)
+}
+
+// DefaultVariant is the scheme HecateShell has always generated with
+const DefaultVariant = VariantFidelity
+
+// Scheme is a Material Design 3 color scheme: six tonal palettes plus the
+// dark/light decision, from which every color role is derived.
+type Scheme struct {
+	Source  Hct
+	Variant Variant
+	IsDark  bool
+
+	Primary        *TonalPalette
+	Secondary      *TonalPalette
+	Tertiary       *TonalPalette
+	Neutral        *TonalPalette
+	NeutralVariant *TonalPalette
+	Error          *TonalPalette
+
+	Semantic map[string]*TonalPalette // SemanticColors by name, harmonized toward the source
+
+	terminal *[16]uint32 // ANSI colors, computed on first use
+}
+
+// NewScheme builds the scheme for a source color
+func NewScheme(source uint32, variant Variant, isDark bool) *Scheme {
+	hct := HctFromArgb(source)
+	s := &Scheme{
+		Source:  hct,
+		Variant: variant,
+		IsDark:  isDark,
+		Error:   NewTonalPalette(25.0, 84.0),
+	}
+
+	hue := hct.Hue()
+	switch variant {
+	case VariantTonalSpot:
+		s.Primary = NewTonalPalette(hue, 36.0)
+		s.Secondary = NewTonalPalette(hue, 16.0)
+		s.Tertiary = NewTonalPalette(sanitizeDegrees(hue+60.0), 24.0)
+		s.Neutral = NewTonalPalette(hue, 6.0)
+		s.NeutralVariant = NewTonalPalette(hue, 8.0)
+	case VariantExpressive:
+		hues := []float64{0, 21, 51, 121, 151, 191, 271, 321, 360}
+		s.Primary = NewTonalPalette(sanitizeDegrees(hue+240.0), 40.0)
+		s.Secondary = NewTonalPalette(rotatedHue(hue, hues, []float64{45, 95, 45, 20, 45, 90, 45, 45, 45}), 24.0)
+		s.Tertiary = NewTonalPalette(rotatedHue(hue, hues, []float64{120, 120, 20, 45, 20, 15, 20, 120, 120}), 32.0)
+		s.Neutral = NewTonalPalette(sanitizeDegrees(hue+15.0), 8.0)
+		s.NeutralVariant = NewTonalPalette(sanitizeDegrees(hue+15.0), 12.0)
+	case VariantVibrant:
+		hues := []float64{0, 41, 61, 101, 131, 181, 251, 301, 360}
+		s.Primary = NewTonalPalette(hue, 200.0)
+		s.Secondary = NewTonalPalette(rotatedHue(hue, hues, []float64{18, 15, 10, 12, 15, 18, 15, 12, 12}), 24.0)
+		s.Tertiary = NewTonalPalette(rotatedHue(hue, hues, []float64{35, 30, 20, 25, 30, 35, 30, 25, 25}), 32.0)
+		s.Neutral = NewTonalPalette(hue, 10.0)
+		s.NeutralVariant = NewTonalPalette(hue, 12.0)
+	case VariantMonochrome:
+		s.Primary = NewTonalPalette(hue, 0.0)
+		s.Secondary = NewTonalPalette(hue, 0.0)
+		s.Tertiary = NewTonalPalette(hue, 0.0)
+		s.Neutral = NewTonalPalette(hue, 0.0)
+		s.NeutralVariant = NewTonalPalette(hue, 0.0)
+	case VariantNeutral:
+		s.Primary = NewTonalPalette(hue, 12.0)
+		s.Secondary = NewTonalPalette(hue, 8.0)
+		s.Tertiary = NewTonalPalette(hue, 16.0)
+		s.Neutral = NewTonalPalette(hue, 2.0)
+		s.NeutralVariant = NewTonalPalette(hue, 2.0)
+	case VariantRainbow:
+		s.Primary = NewTonalPalette(hue, 48.0)
+		s.Secondary = NewTonalPalette(hue, 16.0)
+		s.Tertiary = NewTonalPalette(sanitizeDegrees(hue+60.0), 24.0)
+		s.Neutral = NewTonalPalette(hue, 0.0)
+		s.NeutralVariant = NewTonalPalette(hue, 0.0)
+	case VariantFruitSalad:
+		s.Primary = NewTonalPalette(sanitizeDegrees(hue-50.0), 48.0)
+		s.Secondary = NewTonalPalette(sanitizeDegrees(hue-50.0), 36.0)
+		s.Tertiary = NewTonalPalette(hue, 36.0)
+		s.Neutral = NewTonalPalette(hue, 10.0)
+		s.NeutralVariant = NewTonalPalette(hue, 16.0)
+	case VariantContent:
+		s.Primary = NewTonalPalette(hue, hct.Chroma())
+		s.Secondary = NewTonalPalette(hue, math.Max(hct.Chroma()-32.0, hct.Chroma()*0.5))
+		s.Tertiary = TonalPaletteFromHct(fixIfDisliked(newTemperatureCache(hct).analogous(3, 6)[2]))
+		s.Neutral = NewTonalPalette(hue, hct.Chroma()/8.0)
+		s.NeutralVariant = NewTonalPalette(hue, hct.Chroma()/8.0+4.0)
+	default:
+		// Fidelity
+		s.Variant = VariantFidelity
+		s.Primary = NewTonalPalette(hue, hct.Chroma())
+		s.Secondary = NewTonalPalette(hue, math.Max(hct.Chroma()-32.0, hct.Chroma()*0.5))
+		s.Tertiary = TonalPaletteFromHct(fixIfDisliked(newTemperatureCache(hct).complement()))
+		s.Neutral = NewTonalPalette(hue, hct.Chroma()/8.0)
+		s.NeutralVariant = NewTonalPalette(hue, hct.Chroma()/8.0+4.0)
+	}
+
+	s.Semantic = make(map[string]*TonalPalette, len(SemanticColors))
+	for _, c := range SemanticColors {
+		s.Semantic[c.Name] = TonalPaletteFromHct(HctFromArgb(Harmonize(c.Seed, source)))
+	}
+
+	return s
+}
+
+// role maps a Material color role (in matugen's snake_case naming) to its color
+type role struct {
+	name  string
+	color func(s *Scheme) uint32
+}
+
+// roles lists every color role exposed to templates, in output order
+var roles []role
+
+// The Material color roles, defined as in material-color-utilities' MaterialDynamicColors
+var (
+	background, onBackground, surface, onSurface, surfaceDim, surfaceBright         *dynamicColor
+	surfaceContainerLowest, surfaceContainerLow, surfaceContainer                   *dynamicColor
+	surfaceContainerHigh, surfaceContainerHighest                                   *dynamicColor
+	surfaceVariant, onSurfaceVariant, surfaceTint, inverseSurface, inverseOnSurface *dynamicColor
+	outline, outlineVariant, shadow, scrim                                          *dynamicColor
+	primary, onPrimary, primaryContainer, onPrimaryContainer, inversePrimary        *dynamicColor
+	primaryFixed, primaryFixedDim, onPrimaryFixed, onPrimaryFixedVariant            *dynamicColor
+	secondary, onSecondary, secondaryContainer, onSecondaryContainer                *dynamicColor
+	secondaryFixed, secondaryFixedDim, onSecondaryFixed, onSecondaryFixedVariant    *dynamicColor
+	tertiary, onTertiary, tertiaryContainer, onTertiaryContainer                    *dynamicColor
+	tertiaryFixed, tertiaryFixedDim, onTertiaryFixed, onTertiaryFixedVariant        *dynamicColor
+	errorColor, onError, errorContainer, onErrorContainer                           *dynamicColor
+)
+
+func init() {
+	neutral := func(s *Scheme) *TonalPalette { return s.Neutral }
+	neutralVariant := func(s *Scheme) *TonalPalette { return s.NeutralVariant }
+
+	background = &dynamicColor{palette: neutral, tone: modeTone(6, 98), isBackground: true}
+	onBackground = &dynamicColor{palette: neutral, tone: modeTone(90, 10), background: is(background), contrast: 3}
+	surface = &dynamicColor{palette: neutral, tone: modeTone(6, 98), isBackground: true}
+	onSurface = &dynamicColor{palette: neutral, tone: modeTone(90, 10), background: highestSurface, contrast: 7}
+	surfaceDim = &dynamicColor{palette: neutral, tone: modeTone(6, 87), isBackground: true}
+	surfaceBright = &dynamicColor{palette: neutral, tone: modeTone(24, 98), isBackground: true}
+	surfaceContainerLowest = &dynamicColor{palette: neutral, tone: modeTone(4, 100), isBackground: true}
+	surfaceContainerLow = &dynamicColor{palette: neutral, tone: modeTone(10, 96), isBackground: true}
+	surfaceContainer = &dynamicColor{palette: neutral, tone: modeTone(12, 94), isBackground: true}
+	surfaceContainerHigh = &dynamicColor{palette: neutral, tone: modeTone(17, 92), isBackground: true}
+	surfaceContainerHighest = &dynamicColor{palette: neutral, tone: modeTone(22, 90), isBackground: true}
+	surfaceVariant = &dynamicColor{palette: neutralVariant, tone: modeTone(30, 90), isBackground: true}
+	onSurfaceVariant = &dynamicColor{palette: neutralVariant, tone: modeTone(80, 30), background: highestSurface, contrast: 4.5}
+	inverseSurface = &dynamicColor{palette: neutral, tone: modeTone(90, 20)}
+	inverseOnSurface = &dynamicColor{palette: neutral, tone: modeTone(20, 95), background: is(inverseSurface), contrast: 7}
+	outline = &dynamicColor{palette: neutralVariant, tone: modeTone(60, 50), background: highestSurface, contrast: 3}
+	outlineVariant = &dynamicColor{palette: neutralVariant, tone: modeTone(30, 80), background: highestSurface, contrast: 1}
+	shadow = &dynamicColor{palette: neutral, tone: fixedTone(0)}
+	scrim = &dynamicColor{palette: neutral, tone: fixedTone(0)}
+
+	primaryPalette := func(s *Scheme) *TonalPalette { return s.Primary }
+	surfaceTint = &dynamicColor{palette: primaryPalette, tone: modeTone(80, 40), isBackground: true}
+	primary, onPrimary, primaryContainer, onPrimaryContainer = accentColors(primaryPalette,
+		func(s *Scheme) float64 {
+			if s.isMonochrome() {
+				return s.pick(100, 0)
+			}
+			return s.pick(80, 40)
+		},
+		func(s *Scheme) float64 {
+			if s.isMonochrome() {
+				return s.pick(10, 90)
+			}
+			return s.pick(20, 100)
+		},
+		func(s *Scheme) float64 {
+			if s.isFidelity() {
+				return s.Source.Tone()
+			}
+			if s.isMonochrome() {
+				return s.pick(85, 25)
+			}
+			return s.pick(30, 90)
+		},
+		func(s *Scheme) float64 {
+			if s.isFidelity() {
+				return foregroundTone(primaryContainer.tone(s), 4.5)
+			}
+			if s.isMonochrome() {
+				return s.pick(0, 100)
+			}
+			return s.pick(90, 10)
+		})
+	inversePrimary = &dynamicColor{palette: primaryPalette, tone: modeTone(40, 80), background: is(inverseSurface), contrast: 4.5}
+	primaryFixed, primaryFixedDim, onPrimaryFixed, onPrimaryFixedVariant = fixedColors(primaryPalette,
+		[4]float64{90, 80, 10, 30}, [4]float64{40, 30, 100, 90})
+
+	secondaryPalette := func(s *Scheme) *TonalPalette { return s.Secondary }
+	secondary, onSecondary, secondaryContainer, onSecondaryContainer = accentColors(secondaryPalette,
+		modeTone(80, 40),
+		func(s *Scheme) float64 {
+			if s.isMonochrome() {
+				return s.pick(10, 100)
+			}
+			return s.pick(20, 100)
+		},
+		func(s *Scheme) float64 {
+			if s.isMonochrome() {
+				return s.pick(30, 85)
+			}
+			if !s.isFidelity() {
+				return s.pick(30, 90)
+			}
+			return findDesiredChromaByTone(s.Secondary.Hue(), s.Secondary.Chroma(), s.pick(30, 90), !s.IsDark)
+		},
+		func(s *Scheme) float64 {
+			if s.isFidelity() && !s.isMonochrome() {
+				return foregroundTone(secondaryContainer.tone(s), 4.5)
+			}
+			return s.pick(90, 10)
+		})
+	secondaryFixed, secondaryFixedDim, onSecondaryFixed, onSecondaryFixedVariant = fixedColors(secondaryPalette,
+		[4]float64{90, 80, 10, 30}, [4]float64{80, 70, 10, 25})
+
+	tertiaryPalette := func(s *Scheme) *TonalPalette { return s.Tertiary }
+	tertiary, onTertiary, tertiaryContainer, onTertiaryContainer = accentColors(tertiaryPalette,
+		func(s *Scheme) float64 {
+			if s.isMonochrome() {
+				return s.pick(90, 25)
+			}
+			return s.pick(80, 40)
+		},
+		func(s *Scheme) float64 {
+			if s.isMonochrome() {
+				return s.pick(10, 90)
+			}
+			return s.pick(20, 100)
+		},
+		func(s *Scheme) float64 {
+			if s.isMonochrome() {
+				return s.pick(60, 49)
+			}
+			if !s.isFidelity() {
+				return s.pick(30, 90)
+			}
+			return fixIfDisliked(s.Tertiary.ToneHct(s.Source.Tone())).Tone()
+		},
+		func(s *Scheme) float64 {
+			if s.isMonochrome() {
+				return s.pick(0, 100)
+			}
+			if !s.isFidelity() {
+				return s.pick(90, 10)
+			}
+			return foregroundTone(tertiaryContainer.tone(s), 4.5)
+		})
+	tertiaryFixed, tertiaryFixedDim, onTertiaryFixed, onTertiaryFixedVariant = fixedColors(tertiaryPalette,
+		[4]float64{90, 80, 10, 30}, [4]float64{40, 30, 100, 90})
+
+	errorColor, onError, errorContainer, onErrorContainer = errorColors(func(s *Scheme) *TonalPalette { return s.Error })
+
+	roles = []role{
+		{"source_color", func(s *Scheme) uint32 { return s.Source.Argb() }},
+
+		{"primary", primary.argb},
+		{"on_primary", onPrimary.argb},
+		{"primary_container", primaryContainer.argb},
+		{"on_primary_container", onPrimaryContainer.argb},
+		{"inverse_primary", inversePrimary.argb},
+		{"primary_fixed", primaryFixed.argb},
+		{"primary_fixed_dim", primaryFixedDim.argb},
+		{"on_primary_fixed", onPrimaryFixed.argb},
+		{"on_primary_fixed_variant", onPrimaryFixedVariant.argb},
+
+		{"secondary", secondary.argb},
+		{"on_secondary", onSecondary.argb},
+		{"secondary_container", secondaryContainer.argb},
+		{"on_secondary_container", onSecondaryContainer.argb},
+		{"secondary_fixed", secondaryFixed.argb},
+		{"secondary_fixed_dim", secondaryFixedDim.argb},
+		{"on_secondary_fixed", onSecondaryFixed.argb},
+		{"on_secondary_fixed_variant", onSecondaryFixedVariant.argb},
+
+		{"tertiary", tertiary.argb},
+		{"on_tertiary", onTertiary.argb},
+		{"tertiary_container", tertiaryContainer.argb},
+		{"on_tertiary_container", onTertiaryContainer.argb},
+		{"tertiary_fixed", tertiaryFixed.argb},
+		{"tertiary_fixed_dim", tertiaryFixedDim.argb},
+		{"on_tertiary_fixed", onTertiaryFixed.argb},
+		{"on_tertiary_fixed_variant", onTertiaryFixedVariant.argb},
+
+		{"error", errorColor.argb},
+		{"on_error", onError.argb},
+		{"error_container", errorContainer.argb},
+		{"on_error_container", onErrorContainer.argb},
+
+		{"background", background.argb},
+		{"on_background", onBackground.argb},
+		{"surface", surface.argb},
+		{"on_surface", onSurface.argb},
+		{"surface_dim", surfaceDim.argb},
+		{"surface_bright", surfaceBright.argb},
+		{"surface_container_lowest", surfaceContainerLowest.argb},
+		{"surface_container_low", surfaceContainerLow.argb},
+		{"surface_container", surfaceContainer.argb},
+		{"surface_container_high", surfaceContainerHigh.argb},
+		{"surface_container_highest", surfaceContainerHighest.argb},
+		{"surface_variant", surfaceVariant.argb},
+		{"on_surface_variant", onSurfaceVariant.argb},
+		{"surface_tint", surfaceTint.argb},
+		{"inverse_surface", inverseSurface.argb},
+		{"inverse_on_surface", inverseOnSurface.argb},
+
+		{"outline", outline.argb},
+		{"outline_variant", outlineVariant.argb},
+		{"shadow", shadow.argb},
+		{"scrim", scrim.argb},
+	}
+
+	roles = append(roles, semanticRoles()...)
+	for i, name := range TerminalRoles {
+		roles = append(roles, role{name, func(s *Scheme) uint32 { return s.terminalColors()[i] }})
+	}
+}
+
+// accentColors builds an accent, its container and the colors drawn on them. The accent
+// and container keep 10 tones apart, and both contrast with the highest surface.
+func accentColors(palette func(s *Scheme) *TonalPalette, tone, onTone, containerTone, onContainerTone func(s *Scheme) float64) (accent, on, container, onContainer *dynamicColor) {
+	accent = &dynamicColor{palette: palette, tone: tone, isBackground: true, background: highestSurface, contrast: 4.5}
+	container = &dynamicColor{palette: palette, tone: containerTone, isBackground: true, background: highestSurface, contrast: 1}
+	pair := func(s *Scheme) toneDeltaPair {
+		return toneDeltaPair{a: container, b: accent, delta: 10, polarity: polarityNearer}
+	}
+	accent.pair = pair
+	container.pair = pair
+	on = &dynamicColor{palette: palette, tone: onTone, background: is(accent), contrast: 7}
+	onContainer = &dynamicColor{palette: palette, tone: onContainerTone, background: is(container), contrast: 7}
+	return accent, on, container, onContainer
+}
+
+// errorColors builds error-like accent colors, which have the same tones in every variant
+func errorColors(palette func(s *Scheme) *TonalPalette) (accent, on, container, onContainer *dynamicColor) {
+	return accentColors(palette, modeTone(80, 40), modeTone(20, 100), modeTone(30, 90), modeTone(90, 10))
+}
+
+// fixedColors builds the fixed, fixed dim, on fixed and on fixed variant colors of a
+// palette, which keep their tones in both modes. Monochrome schemes use their own tones.
+func fixedColors(palette func(s *Scheme) *TonalPalette, tones, monochromeTones [4]float64) (fixed, fixedDim, onFixed, onFixedVariant *dynamicColor) {
+	tone := func(i int) func(s *Scheme) float64 {
+		return func(s *Scheme) float64 {
+			if s.isMonochrome() {
+				return monochromeTones[i]
+			}
+			return tones[i]
+		}
+	}
+	fixed = &dynamicColor{palette: palette, tone: tone(0), isBackground: true, background: highestSurface, contrast: 1}
+	fixedDim = &dynamicColor{palette: palette, tone: tone(1), isBackground: true, background: highestSurface, contrast: 1}
+	pair := func(s *Scheme) toneDeltaPair {
+		return toneDeltaPair{a: fixed, b: fixedDim, delta: 10, polarity: polarityLighter, stayTogether: true}
+	}
+	fixed.pair = pair
+	fixedDim.pair = pair
+	onFixed = &dynamicColor{palette: palette, tone: tone(2), background: is(fixedDim), secondBackground: is(fixed), contrast: 7}
+	onFixedVariant = &dynamicColor{palette: palette, tone: tone(3), background: is(fixedDim), secondBackground: is(fixed), contrast: 4.5}
+	return fixed, fixedDim, onFixed, onFixedVariant
+}
+
+// semanticRoles gives each semantic color the same roles as error
+func semanticRoles() []role {
+	var out []role
+	for _, c := range SemanticColors {
+		name := c.Name
+		accent, on, container, onContainer := errorColors(func(s *Scheme) *TonalPalette { return s.Semantic[name] })
+		out = append(out,
+			role{name, accent.argb},
+			role{"on_" + name, on.argb},
+			role{name + "_container", container.argb},
+			role{"on_" + name + "_container", onContainer.argb},
+		)
+	}
+	return out
+}
+
+// highestSurface is the surface accents must contrast with: the brightest one in dark
+// mode and the dimmest one in light mode
+func highestSurface(s *Scheme) *dynamicColor {
+	if s.IsDark {
+		return surfaceBright
+	}
+	return surfaceDim
+}
+
+// is returns a background function for a fixed role
+func is(c *dynamicColor) func(s *Scheme) *dynamicColor {
+	return func(s *Scheme) *dynamicColor { return c }
+}
+
+// modeTone returns a tone function with one tone for dark mode and one for light mode
+func modeTone(dark, light float64) func(s *Scheme) float64 {
+	return func(s *Scheme) float64 { return s.pick(dark, light) }
+}
+
+// fixedTone returns a tone function with the same tone in both modes
+func fixedTone(tone float64) func(s *Scheme) float64 {
+	return func(s *Scheme) float64 { return tone }
+}
+
+// RoleNames returns every color role name a scheme provides, in a stable order
+func RoleNames() []string {
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = r.name
+	}
+	return names
+}
+
+// Colors resolves every color role of the scheme
+func (s *Scheme) Colors() map[string]uint32 {
+	colors := make(map[string]uint32, len(roles))
+	for _, r := range roles {
+		colors[r.name] = r.color(s)
+	}
+	return colors
+}
+
+// tone picks the dark or light tone from a palette
+func (s *Scheme) tone(p *TonalPalette, dark, light int) uint32 {
+	if s.IsDark {
+		return p.Tone(dark)
+	}
+	return p.Tone(light)
+}
+
+// pick returns the dark or light tone
+func (s *Scheme) pick(dark, light float64) float64 {
+	if s.IsDark {
+		return dark
+	}
+	return light
+}
+
+// isFidelity reports whether container roles should follow the source tone
+func (s *Scheme) isFidelity() bool {
+	return s.Variant == VariantFidelity || s.Variant == VariantContent
+}
+
+// isMonochrome reports whether accent roles use pure black and white
+func (s *Scheme) isMonochrome() bool {
+	return s.Variant == VariantMonochrome
+}
+
+// toneOf returns a palette color at a possibly fractional tone
+func toneOf(p *TonalPalette, tone float64) uint32 {
+	if tone == math.Trunc(tone) {
+		return p.Tone(int(tone))
+	}
+	return p.ToneHct(tone).Argb()
+}
+
+// rotatedHue rotates the source hue by the amount listed for the hue range it falls in
+func rotatedHue(sourceHue float64, hues, rotations []float64) float64 {
+	if len(rotations) == 1 {
+		return sanitizeDegrees(sourceHue + rotations[0])
+	}
+	for i := 0; i < len(hues)-1; i++ {
+		if hues[i] < sourceHue && sourceHue < hues[i+1] {
+			return sanitizeDegrees(sourceHue + rotations[i])
+		}
+	}
+	return sourceHue
+}
@@ -0,0 +1,191 @@
+package material
+
+import (
+	"math"
+	"testing"
+)
+
+// Reference values from material-color-utilities' dynamic scheme tests
+func TestTonalSpotColors(t *testing.T) {
+	tests := []struct {
+		isDark bool
+		role   string
+		want   uint32
+	}{
+		{false, "primary", 0xff555992},
+		{false, "primary_container", 0xffe0e0ff},
+		{false, "on_primary_container", 0xff11144b},
+		{false, "secondary", 0xff5c5d72},
+		{false, "secondary_container", 0xffe1e0f9},
+		{false, "tertiary_container", 0xffffd8ee},
+		{false, "surface", 0xfffbf8ff},
+		{true, "primary", 0xffbec2ff},
+		{true, "primary_container", 0xff3e4278},
+		{true, "on_primary_container", 0xffe0e0ff},
+		{true, "secondary_container", 0xff444559},
+		{true, "surface", 0xff131318},
+	}
+	light := NewScheme(0xff0000ff, VariantTonalSpot, false).Colors()
+	dark := NewScheme(0xff0000ff, VariantTonalSpot, true).Colors()
+	for _, tt := range tests {
+		colors := light
+		if tt.isDark {
+			colors = dark
+		}
+		if got := colors[tt.role]; got != tt.want {
+			t.Errorf("tonal spot (dark %v) %s = %08x, want %08x", tt.isDark, tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestVariantPalettes(t *testing.T) {
+	source := HctFromArgb(0xff0000ff)
+	hue, chroma := source.Hue(), source.Chroma()
+	type palette struct{ hue, chroma float64 }
+	skip := palette{-1, -1} // Derived from the color temperature, see TestContentTertiaryPalette
+	rotated := func(degrees float64) float64 { return sanitizeDegrees(hue + degrees) }
+	tests := []struct {
+		variant                                           Variant
+		primary, secondary, tertiary, neutral, neutralVar palette
+	}{
+		{VariantTonalSpot, palette{hue, 36}, palette{hue, 16}, palette{rotated(60), 24}, palette{hue, 6}, palette{hue, 8}},
+		{VariantExpressive, palette{rotated(240), 40}, palette{rotated(45), 24}, palette{rotated(20), 32}, palette{rotated(15), 8}, palette{rotated(15), 12}},
+		{VariantVibrant, palette{hue, 200}, palette{rotated(15), 24}, palette{rotated(30), 32}, palette{hue, 10}, palette{hue, 12}},
+		{VariantMonochrome, palette{hue, 0}, palette{hue, 0}, palette{hue, 0}, palette{hue, 0}, palette{hue, 0}},
+		{VariantNeutral, palette{hue, 12}, palette{hue, 8}, palette{hue, 16}, palette{hue, 2}, palette{hue, 2}},
+		{VariantRainbow, palette{hue, 48}, palette{hue, 16}, palette{rotated(60), 24}, palette{hue, 0}, palette{hue, 0}},
+		{VariantFruitSalad, palette{rotated(-50), 48}, palette{rotated(-50), 36}, palette{hue, 36}, palette{hue, 10}, palette{hue, 16}},
+		{VariantContent, palette{hue, chroma}, palette{hue, chroma - 32}, skip, palette{hue, chroma / 8}, palette{hue, chroma/8 + 4}},
+		{VariantFidelity, palette{hue, chroma}, palette{hue, chroma - 32}, skip, palette{hue, chroma / 8}, palette{hue, chroma/8 + 4}},
+	}
+	for _, tt := range tests {
+		s := NewScheme(0xff0000ff, tt.variant, false)
+		check := func(name string, p *TonalPalette, want palette) {
+			if want == skip {
+				return
+			}
+			if math.Abs(p.Hue()-want.hue) > 0.001 || math.Abs(p.Chroma()-want.chroma) > 0.001 {
+				t.Errorf("%s %s palette = %.3f/%.3f, want %.3f/%.3f", tt.variant, name, p.Hue(), p.Chroma(), want.hue, want.chroma)
+			}
+		}
+		check("primary", s.Primary, tt.primary)
+		check("secondary", s.Secondary, tt.secondary)
+		check("tertiary", s.Tertiary, tt.tertiary)
+		check("neutral", s.Neutral, tt.neutral)
+		check("neutral variant", s.NeutralVariant, tt.neutralVar)
+	}
+}
+
+func TestContentTertiaryPalette(t *testing.T) {
+	source := HctFromArgb(0xff0000ff)
+	tests := []struct {
+		variant Variant
+		want    Hct
+	}{
+		{VariantContent, fixIfDisliked(newTemperatureCache(source).analogous(3, 6)[2])},
+		{VariantFidelity, fixIfDisliked(newTemperatureCache(source).complement())},
+	}
+	for _, tt := range tests {
+		p := NewScheme(0xff0000ff, tt.variant, false).Tertiary
+		if p.Hue() != tt.want.Hue() || p.Chroma() != tt.want.Chroma() {
+			t.Errorf("%s tertiary palette = %.3f/%.3f, want %.3f/%.3f", tt.variant, p.Hue(), p.Chroma(), tt.want.Hue(), tt.want.Chroma())
+		}
+	}
+}
+
+func TestMonochromeTones(t *testing.T) {
+	tests := []struct {
+		isDark bool
+		tones  map[string]float64
+	}{
+		{true, map[string]float64{
+			"primary": 100, "on_primary": 10, "primary_container": 85, "on_primary_container": 0,
+			"secondary": 80, "on_secondary": 10, "secondary_container": 30, "on_secondary_container": 90,
+			"tertiary": 90, "on_tertiary": 10, "tertiary_container": 60, "on_tertiary_container": 0,
+		}},
+		{false, map[string]float64{
+			"primary": 0, "on_primary": 90, "primary_container": 25, "on_primary_container": 100,
+			"secondary": 40, "on_secondary": 100, "secondary_container": 85, "on_secondary_container": 10,
+			"tertiary": 25, "on_tertiary": 90, "tertiary_container": 49, "on_tertiary_container": 100,
+			"primary_fixed": 40, "primary_fixed_dim": 30, "on_primary_fixed": 100, "on_primary_fixed_variant": 90,
+			"secondary_fixed": 80, "secondary_fixed_dim": 70, "on_secondary_fixed": 10, "on_secondary_fixed_variant": 25,
+			"tertiary_fixed": 40, "tertiary_fixed_dim": 30, "on_tertiary_fixed": 100, "on_tertiary_fixed_variant": 90,
+		}},
+	}
+	for _, tt := range tests {
+		colors := NewScheme(0xff0000ff, VariantMonochrome, tt.isDark).Colors()
+		for name, want := range tt.tones {
+			if got := LstarFromArgb(colors[name]); math.Abs(got-want) > 1 {
+				t.Errorf("monochrome (dark %v) %s tone = %.1f, want %.0f", tt.isDark, name, got, want)
+			}
+		}
+	}
+}
+
+func TestFixedTones(t *testing.T) {
+	want := map[string]float64{
+		"primary_fixed": 90, "primary_fixed_dim": 80, "on_primary_fixed": 10, "on_primary_fixed_variant": 30,
+		"secondary_fixed": 90, "secondary_fixed_dim": 80, "on_secondary_fixed": 10, "on_secondary_fixed_variant": 30,
+		"tertiary_fixed": 90, "tertiary_fixed_dim": 80, "on_tertiary_fixed": 10, "on_tertiary_fixed_variant": 30,
+	}
+	for _, isDark := range []bool{false, true} {
+		colors := NewScheme(0xffff0000, VariantTonalSpot, isDark).Colors()
+		for name, tone := range want {
+			if got := LstarFromArgb(colors[name]); math.Abs(got-tone) > 1 {
+				t.Errorf("tonal spot (dark %v) %s tone = %.1f, want %.0f", isDark, name, got, tone)
+			}
+		}
+	}
+}
+
+func TestFidelityContainers(t *testing.T) {
+	for _, variant := range []Variant{VariantFidelity, VariantContent} {
+		for _, isDark := range []bool{false, true} {
+			s := NewScheme(0xff0000ff, variant, isDark)
+			colors := s.Colors()
+			if got := colors["primary_container"]; got != 0xff0000ff {
+				t.Errorf("%s (dark %v) primary_container = %08x, want the source color", variant, isDark, got)
+			}
+			if isDark {
+				continue
+			}
+			// The light secondary container trades tone for the palette's chroma
+			container := HctFromArgb(colors["secondary_container"])
+			if container.Tone() >= 89.5 || container.Chroma() <= s.Secondary.ToneHct(90).Chroma() {
+				t.Errorf("%s secondary_container = %.1f/%.1f, want a darker, more chromatic tone than 90",
+					variant, container.Chroma(), container.Tone())
+			}
+		}
+	}
+}
+
+// Every variant must keep text readable on its container, like material-color-utilities'
+// contrast guarantees at the standard contrast level
+func TestContrast(t *testing.T) {
+	pairs := [][2]string{
+		{"on_primary", "primary"},
+		{"on_primary_container", "primary_container"},
+		{"on_secondary", "secondary"},
+		{"on_secondary_container", "secondary_container"},
+		{"on_tertiary", "tertiary"},
+		{"on_tertiary_container", "tertiary_container"},
+		{"on_error", "error"},
+		{"on_error_container", "error_container"},
+		{"on_background", "background"},
+		{"on_surface_variant", "surface_bright"},
+		{"on_surface_variant", "surface_dim"},
+	}
+	for _, source := range []uint32{0xffff0000, 0xffffff00, 0xff00ff00, 0xff0000ff} {
+		for _, variant := range Variants {
+			for _, isDark := range []bool{false, true} {
+				colors := NewScheme(source, variant, isDark).Colors()
+				for _, pair := range pairs {
+					if ratio := ContrastRatio(colors[pair[0]], colors[pair[1]]); ratio < 4.5 {
+						t.Errorf("%08x %s (dark %v): %s on %s contrast %.2f, want at least 4.5",
+							source, variant, isDark, pair[0], pair[1], ratio)
+					}
+				}
+			}
+		}
+	}
+}
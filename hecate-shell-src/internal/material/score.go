@@ -0,0 +1,109 @@
+package material
+
+import (
+	"math"
+	"sort"
+)
+
+// FallbackSourceColor is used when an image has no suitable colors (Google Blue)
+const FallbackSourceColor uint32 = 0xff4285f4
+
+const (
+	scoreTargetChroma            = 48.0
+	scoreWeightProportion        = 0.7
+	scoreWeightChromaAbove       = 0.3
+	scoreWeightChromaBelow       = 0.1
+	scoreCutoffChroma            = 5.0
+	scoreCutoffExcitedProportion = 0.01
+)
+
+// Score ranks quantized colors by how well they would work as a theme source.
+// Colors are favored for chroma close to 48 and for covering a large share of the
+// image, and the result is spread out in hue. At most desired colors are returned,
+// and the fallback is returned when nothing qualifies.
+func Score(colorsToPopulation map[uint32]int, desired int) []uint32 {
+	return score(colorsToPopulation, desired, FallbackSourceColor, true)
+}
+
+// score is Score with a custom fallback color. Without filter, colors with almost no
+// chroma or share of the image are ranked too instead of being dropped.
+func score(colorsToPopulation map[uint32]int, desired int, fallback uint32, filter bool) []uint32 {
+	colors := sortedColors(colorsToPopulation)
+
+	var huePopulation [360]float64
+	populationSum := 0.0
+	hcts := make([]Hct, len(colors))
+	for i, argb := range colors {
+		hct := HctFromArgb(argb)
+		hcts[i] = hct
+		population := float64(colorsToPopulation[argb])
+		huePopulation[int(math.Floor(hct.Hue()))%360] += population
+		populationSum += population
+	}
+
+	// Each hue is "excited" by its neighbors within 15 degrees
+	var hueExcitedProportions [360]float64
+	if populationSum > 0 {
+		for hue := 0; hue < 360; hue++ {
+			proportion := huePopulation[hue] / populationSum
+			for i := hue - 14; i < hue+16; i++ {
+				hueExcitedProportions[sanitizeDegreesInt(i)] += proportion
+			}
+		}
+	}
+
+	type scoredHct struct {
+		hct   Hct
+		score float64
+	}
+	var scored []scoredHct
+	for _, hct := range hcts {
+		proportion := hueExcitedProportions[sanitizeDegreesInt(int(math.Round(hct.Hue())))]
+		if filter && (hct.Chroma() < scoreCutoffChroma || proportion <= scoreCutoffExcitedProportion) {
+			continue
+		}
+
+		proportionScore := proportion * 100.0 * scoreWeightProportion
+		chromaWeight := scoreWeightChromaAbove
+		if hct.Chroma() < scoreTargetChroma {
+			chromaWeight = scoreWeightChromaBelow
+		}
+		chromaScore := (hct.Chroma() - scoreTargetChroma) * chromaWeight
+		scored = append(scored, scoredHct{hct: hct, score: proportionScore + chromaScore})
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+
+	// Prefer colors far apart in hue, relaxing the spacing until enough are found
+	var chosen []Hct
+	for differenceDegreesLimit := 90; differenceDegreesLimit >= 15; differenceDegreesLimit-- {
+		chosen = chosen[:0]
+		for _, entry := range scored {
+			duplicate := false
+			for _, c := range chosen {
+				if differenceDegrees(entry.hct.Hue(), c.Hue()) < float64(differenceDegreesLimit) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				chosen = append(chosen, entry.hct)
+			}
+			if len(chosen) >= desired {
+				break
+			}
+		}
+		if len(chosen) >= desired {
+			break
+		}
+	}
+
+	if len(chosen) == 0 {
+		return []uint32{fallback}
+	}
+
+	result := make([]uint32, len(chosen))
+	for i, hct := range chosen {
+		result[i] = hct.Argb()
+	}
+	return result
+}
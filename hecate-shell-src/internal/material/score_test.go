@@ -0,0 +1,39 @@
+package material
+
+import (
+	"slices"
+	"testing"
+)
+
+// Reference values from material-color-utilities' score tests
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		colors   map[uint32]int
+		desired  int
+		fallback uint32
+		filter   bool
+		want     []uint32
+	}{
+		{"prioritizes chroma", map[uint32]int{0xff000000: 1, 0xffffffff: 1, 0xff0000ff: 1}, 4, FallbackSourceColor, true, []uint32{0xff0000ff}},
+		{"prioritizes chroma when proportions equal", map[uint32]int{0xffff0000: 1, 0xff00ff00: 1, 0xff0000ff: 1}, 4, FallbackSourceColor, true, []uint32{0xffff0000, 0xff00ff00, 0xff0000ff}},
+		{"falls back without chromatic colors", map[uint32]int{0xff000000: 1}, 4, FallbackSourceColor, true, []uint32{0xff4285f4}},
+		{"dedupes nearby hues", map[uint32]int{0xff008772: 1, 0xff318477: 1}, 4, FallbackSourceColor, true, []uint32{0xff008772}},
+		{"maximizes hue distance", map[uint32]int{0xff008772: 1, 0xff008587: 1, 0xff007ebc: 1}, 2, FallbackSourceColor, true, []uint32{0xff007ebc, 0xff008772}},
+		{"generated 1", map[uint32]int{0xff7ea16d: 67, 0xffd8ccae: 67, 0xff835c0d: 49}, 3, 0xff8d3819, false, []uint32{0xff7ea16d, 0xffd8ccae, 0xff835c0d}},
+		{"generated 2", map[uint32]int{0xffd33881: 14, 0xff3205cc: 77, 0xff0b48cf: 36, 0xffa08f5d: 81}, 4, 0xff7d772b, true, []uint32{0xff3205cc, 0xffa08f5d, 0xffd33881}},
+		{"generated 3", map[uint32]int{0xffbe94a6: 23, 0xffc33fd7: 42, 0xff899f36: 90, 0xff94c574: 82}, 3, 0xffaa79a4, true, []uint32{0xff94c574, 0xffc33fd7, 0xffbe94a6}},
+		{"generated 4", map[uint32]int{0xffdf241c: 85, 0xff685859: 44, 0xffd06d5f: 34, 0xff561c54: 27, 0xff713090: 88}, 5, 0xff58c19c, false, []uint32{0xffdf241c, 0xff561c54}},
+		{"generated 5", map[uint32]int{0xffbe66f8: 41, 0xff4bbda9: 88, 0xff80f6f9: 44, 0xffab8017: 43, 0xffe89307: 65}, 3, 0xff916691, false, []uint32{0xffab8017, 0xff4bbda9, 0xffbe66f8}},
+		{"generated 6", map[uint32]int{0xff18ea8f: 93, 0xff327593: 18, 0xff066a18: 53, 0xfffa8a23: 74, 0xff04ca1f: 62}, 2, 0xff4c377a, false, []uint32{0xff18ea8f, 0xfffa8a23}},
+		{"generated 7", map[uint32]int{0xff2e05ed: 23, 0xff153e55: 90, 0xff9ab220: 23, 0xff153379: 66, 0xff68bcc3: 81}, 2, 0xfff588dc, true, []uint32{0xff2e05ed, 0xff9ab220}},
+		{"generated 8", map[uint32]int{0xff816ec5: 24, 0xff6dcb94: 19, 0xff3cae91: 98, 0xff5b542f: 25}, 1, 0xff84b0fd, false, []uint32{0xff3cae91}},
+		{"generated 9", map[uint32]int{0xff206f86: 52, 0xff4a620d: 96, 0xfff51401: 85, 0xff2b8ebf: 3, 0xff277766: 59}, 3, 0xff02b415, true, []uint32{0xfff51401, 0xff4a620d, 0xff2b8ebf}},
+		{"generated 10", map[uint32]int{0xff8b1d99: 54, 0xff27effe: 43, 0xff6f558d: 2, 0xff77fdf2: 78}, 4, 0xff5e7a10, true, []uint32{0xff27effe, 0xff8b1d99, 0xff6f558d}},
+	}
+	for _, tt := range tests {
+		if got := score(tt.colors, tt.desired, tt.fallback, tt.filter); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: score = %08x, want %08x", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package material
+
+import (
+	"math"
+	"sort"
+)
+
+// temperatureCache orders the hue wheel of an input color by perceived warmth,
+// which is what Material uses to pick complementary and analogous colors.
+type temperatureCache struct {
+	input      Hct
+	hctsByHue  []Hct
+	tempsByHue []float64
+	inputTemp  float64
+	coldest    float64
+	warmest    float64
+}
+
+func newTemperatureCache(input Hct) *temperatureCache {
+	tc := &temperatureCache{input: input}
+
+	tc.hctsByHue = make([]Hct, 0, 361)
+	tc.tempsByHue = make([]float64, 0, 361)
+	for hue := 0.0; hue <= 360.0; hue += 1.0 {
+		hct := NewHct(hue, input.Chroma(), input.Tone())
+		tc.hctsByHue = append(tc.hctsByHue, hct)
+		tc.tempsByHue = append(tc.tempsByHue, rawTemperature(hct))
+	}
+
+	tc.inputTemp = rawTemperature(input)
+	sorted := append([]float64{tc.inputTemp}, tc.tempsByHue...)
+	sort.Float64s(sorted)
+	tc.coldest = sorted[0]
+	tc.warmest = sorted[len(sorted)-1]
+
+	return tc
+}
+
+// rawTemperature estimates warmth from L*a*b* hue and chroma (Ou, Woodcock and Wright)
+func rawTemperature(hct Hct) float64 {
+	lab := LabFromArgb(hct.Argb())
+	hue := sanitizeDegrees(math.Atan2(lab[2], lab[1]) * 180.0 / math.Pi)
+	chroma := math.Hypot(lab[1], lab[2])
+	return -0.5 + 0.02*math.Pow(chroma, 1.07)*math.Cos(sanitizeDegrees(hue-50.0)*math.Pi/180.0)
+}
+
+// relativeTemperature maps a raw temperature onto 0 (coldest) to 1 (warmest)
+func (tc *temperatureCache) relativeTemperature(temp float64) float64 {
+	span := tc.warmest - tc.coldest
+	if span == 0 {
+		return 0.5
+	}
+	return (temp - tc.coldest) / span
+}
+
+func (tc *temperatureCache) coldestHue() float64 {
+	return tc.extremeHue(func(a, b float64) bool { return a < b })
+}
+
+func (tc *temperatureCache) warmestHue() float64 {
+	return tc.extremeHue(func(a, b float64) bool { return a > b })
+}
+
+func (tc *temperatureCache) extremeHue(better func(a, b float64) bool) float64 {
+	hue := tc.input.Hue()
+	best := tc.inputTemp
+	for i, temp := range tc.tempsByHue {
+		if better(temp, best) {
+			best = temp
+			hue = tc.hctsByHue[i].Hue()
+		}
+	}
+	return hue
+}
+
+// complement returns the color on the opposite side of the temperature scale
+func (tc *temperatureCache) complement() Hct {
+	coldestHue := tc.coldestHue()
+	warmestHue := tc.warmestHue()
+
+	startHueIsColdestToWarmest := isBetween(tc.input.Hue(), coldestHue, warmestHue)
+	startHue, endHue := coldestHue, warmestHue
+	if startHueIsColdestToWarmest {
+		startHue, endHue = warmestHue, coldestHue
+	}
+
+	smallestError := 1000.0
+	answer := tc.hctsByHue[int(math.Round(tc.input.Hue()))]
+
+	complementRelativeTemp := 1.0 - tc.relativeTemperature(tc.inputTemp)
+	for hueAddend := 0.0; hueAddend <= 360.0; hueAddend += 1.0 {
+		hue := sanitizeDegrees(startHue + hueAddend)
+		if !isBetween(hue, startHue, endHue) {
+			continue
+		}
+		index := int(math.Round(hue))
+		relativeTemp := tc.relativeTemperature(tc.tempsByHue[index])
+		if err := math.Abs(complementRelativeTemp - relativeTemp); err < smallestError {
+			smallestError = err
+			answer = tc.hctsByHue[index]
+		}
+	}
+
+	return answer
+}
+
+// analogous returns count colors evenly spaced in temperature around the input,
+// with the wheel split into the given number of divisions.
+func (tc *temperatureCache) analogous(count, divisions int) []Hct {
+	startHue := int(math.Round(tc.input.Hue()))
+	startTemp := tc.relativeTemperature(tc.tempsByHue[startHue])
+	allColors := []Hct{tc.hctsByHue[startHue]}
+
+	absoluteTotalTempDelta := 0.0
+	lastTemp := startTemp
+	for i := 0; i < 360; i++ {
+		hue := sanitizeDegreesInt(startHue + i)
+		temp := tc.relativeTemperature(tc.tempsByHue[hue])
+		absoluteTotalTempDelta += math.Abs(temp - lastTemp)
+		lastTemp = temp
+	}
+
+	hueAddend := 1
+	tempStep := absoluteTotalTempDelta / float64(divisions)
+	totalTempDelta := 0.0
+	lastTemp = startTemp
+	for len(allColors) < divisions {
+		hue := sanitizeDegreesInt(startHue + hueAddend)
+		hct := tc.hctsByHue[hue]
+		temp := tc.relativeTemperature(tc.tempsByHue[hue])
+		totalTempDelta += math.Abs(temp - lastTemp)
+
+		desiredTotalTempDeltaForIndex := float64(len(allColors)) * tempStep
+		indexSatisfied := totalTempDelta >= desiredTotalTempDeltaForIndex
+		indexAddend := 1
+		for indexSatisfied && len(allColors) < divisions {
+			allColors = append(allColors, hct)
+			desiredTotalTempDeltaForIndex = float64(len(allColors)+indexAddend) * tempStep
+			indexSatisfied = totalTempDelta >= desiredTotalTempDeltaForIndex
+			indexAddend++
+		}
+
+		lastTemp = temp
+		hueAddend++
+		if hueAddend > 360 {
+			for len(allColors) < divisions {
+				allColors = append(allColors, hct)
+			}
+			break
+		}
+	}
+
+	answers := []Hct{tc.input}
+
+	ccwCount := (count - 1) / 2
+	for i := 1; i < ccwCount+1; i++ {
+		index := -i
+		for index < 0 {
+			index += len(allColors)
+		}
+		answers = append([]Hct{allColors[index%len(allColors)]}, answers...)
+	}
+
+	cwCount := count - ccwCount - 1
+	for i := 1; i < cwCount+1; i++ {
+		answers = append(answers, allColors[i%len(allColors)])
+	}
+
+	return answers
+}
+
+// isBetween reports whether angle lies on the arc from a to b, going clockwise
+func isBetween(angle, a, b float64) bool {
+	if a < b {
+		return a <= angle && angle <= b
+	}
+	return a <= angle || angle <= b
+}
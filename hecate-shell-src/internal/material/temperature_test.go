@@ -0,0 +1,95 @@
+package material
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// Reference values from material-color-utilities' temperature cache tests
+func TestRawTemperature(t *testing.T) {
+	tests := []struct {
+		argb uint32
+		want float64
+	}{
+		{0xff0000ff, -1.393},
+		{0xffff0000, 2.351},
+		{0xff00ff00, -0.267},
+		{0xffffffff, -0.5},
+		{0xff000000, -0.5},
+	}
+	for _, tt := range tests {
+		if got := rawTemperature(HctFromArgb(tt.argb)); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("rawTemperature(%08x) = %.3f, want %.3f", tt.argb, got, tt.want)
+		}
+	}
+}
+
+func TestComplement(t *testing.T) {
+	tests := []struct{ argb, want uint32 }{
+		{0xff0000ff, 0xff9d0002},
+		{0xffff0000, 0xff007bfc},
+		{0xff00ff00, 0xffffd2c9},
+		{0xffffffff, 0xffffffff},
+		{0xff000000, 0xff000000},
+	}
+	for _, tt := range tests {
+		if got := newTemperatureCache(HctFromArgb(tt.argb)).complement().Argb(); got != tt.want {
+			t.Errorf("complement(%08x) = %08x, want %08x", tt.argb, got, tt.want)
+		}
+	}
+}
+
+func TestAnalogous(t *testing.T) {
+	tests := []struct {
+		argb uint32
+		want []uint32
+	}{
+		{0xff0000ff, []uint32{0xff00590c, 0xff00564e, 0xff0000ff, 0xff6700cc, 0xff81009f}},
+		{0xffff0000, []uint32{0xfff60082, 0xfffc004c, 0xffff0000, 0xffd95500, 0xffaf7200}},
+		{0xff00ff00, []uint32{0xffcee900, 0xff92f500, 0xff00ff00, 0xff00fd6f, 0xff00fab3}},
+		{0xff000000, []uint32{0xff000000, 0xff000000, 0xff000000, 0xff000000, 0xff000000}},
+	}
+	for _, tt := range tests {
+		var got []uint32
+		for _, hct := range newTemperatureCache(HctFromArgb(tt.argb)).analogous(5, 12) {
+			got = append(got, hct.Argb())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("analogous(%08x) = %08x, want %08x", tt.argb, got, tt.want)
+		}
+	}
+}
+
+// Reference values from material-color-utilities' blend tests
+func TestHarmonize(t *testing.T) {
+	tests := []struct{ design, source, want uint32 }{
+		{0xffff0000, 0xff0000ff, 0xfffb0057},
+		{0xffff0000, 0xff00ff00, 0xffd85600},
+		{0xffff0000, 0xffffff00, 0xffd85600},
+		{0xff0000ff, 0xff00ff00, 0xff0047a3},
+		{0xff0000ff, 0xffff0000, 0xff5700dc},
+		{0xff0000ff, 0xffffff00, 0xff0047a3},
+	}
+	for _, tt := range tests {
+		if got := Harmonize(tt.design, tt.source); got != tt.want {
+			t.Errorf("Harmonize(%08x, %08x) = %08x, want %08x", tt.design, tt.source, got, tt.want)
+		}
+	}
+}
+
+// Reference values from material-color-utilities' dislike analyzer tests
+func TestFixIfDisliked(t *testing.T) {
+	for _, argb := range []uint32{0xff95884b, 0xff716b40, 0xffb08e00, 0xff4c4308, 0xff464521} {
+		hct := HctFromArgb(argb)
+		if !isDisliked(hct) {
+			t.Errorf("isDisliked(%08x) = false, want true", argb)
+		}
+		if isDisliked(fixIfDisliked(hct)) {
+			t.Errorf("fixIfDisliked(%08x) is still disliked", argb)
+		}
+	}
+	if isDisliked(NewHct(100, 50, 67)) {
+		t.Error("tone 67 olive is disliked")
+	}
+}
@@ -0,0 +1,247 @@
+package material
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color science ported from material-color-utilities (https://github.com/material-foundation/material-color-utilities)
+// Colors are packed as 0xAARRGGBB, the same layout matugen and the Material libraries use.
+
+var srgbToXyz = [3][3]float64{
+	{0.41233895, 0.35762064, 0.18051042},
+	{0.2126, 0.7152, 0.0722},
+	{0.01932141, 0.11916382, 0.95034478},
+}
+
+var xyzToSrgb = [3][3]float64{
+	{3.2413774792388685, -1.5376652402851851, -0.49885366846268053},
+	{-0.9691452513005321, 1.8758853451067872, 0.04156585616912061},
+	{0.05562093689691305, -0.20395524564742123, 1.0571799111220335},
+}
+
+var whitePointD65 = [3]float64{95.047, 100.0, 108.883}
+
+// ArgbFromRgb packs red, green and blue components into an opaque ARGB color
+func ArgbFromRgb(r, g, b int) uint32 {
+	return 0xff000000 | uint32(r&0xff)<<16 | uint32(g&0xff)<<8 | uint32(b&0xff)
+}
+
+// AlphaFromArgb returns the alpha component of a color
+func AlphaFromArgb(argb uint32) int {
+	return int(argb>>24) & 0xff
+}
+
+// RedFromArgb returns the red component of a color
+func RedFromArgb(argb uint32) int {
+	return int(argb>>16) & 0xff
+}
+
+// GreenFromArgb returns the green component of a color
+func GreenFromArgb(argb uint32) int {
+	return int(argb>>8) & 0xff
+}
+
+// BlueFromArgb returns the blue component of a color
+func BlueFromArgb(argb uint32) int {
+	return int(argb) & 0xff
+}
+
+// HexFromArgb formats a color as #rrggbb
+func HexFromArgb(argb uint32) string {
+	return fmt.Sprintf("#%02x%02x%02x", RedFromArgb(argb), GreenFromArgb(argb), BlueFromArgb(argb))
+}
+
+// ArgbFromHex parses #rgb, #rrggbb or #aarrggbb (the leading # is optional)
+func ArgbFromHex(hex string) (uint32, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	switch len(s) {
+	case 3:
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+		fallthrough
+	case 6:
+		s = "ff" + s
+	case 8:
+	default:
+		return 0, fmt.Errorf("invalid hex color: %q", hex)
+	}
+
+	value, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hex color: %q", hex)
+	}
+	return uint32(value), nil
+}
+
+// ArgbFromXyz converts a color from CIE XYZ to ARGB, clipping to the sRGB gamut
+func ArgbFromXyz(x, y, z float64) uint32 {
+	linearR := xyzToSrgb[0][0]*x + xyzToSrgb[0][1]*y + xyzToSrgb[0][2]*z
+	linearG := xyzToSrgb[1][0]*x + xyzToSrgb[1][1]*y + xyzToSrgb[1][2]*z
+	linearB := xyzToSrgb[2][0]*x + xyzToSrgb[2][1]*y + xyzToSrgb[2][2]*z
+	return ArgbFromRgb(delinearized(linearR), delinearized(linearG), delinearized(linearB))
+}
+
+// XyzFromArgb converts a color from ARGB to CIE XYZ
+func XyzFromArgb(argb uint32) [3]float64 {
+	r := linearized(RedFromArgb(argb))
+	g := linearized(GreenFromArgb(argb))
+	b := linearized(BlueFromArgb(argb))
+	return [3]float64{
+		srgbToXyz[0][0]*r + srgbToXyz[0][1]*g + srgbToXyz[0][2]*b,
+		srgbToXyz[1][0]*r + srgbToXyz[1][1]*g + srgbToXyz[1][2]*b,
+		srgbToXyz[2][0]*r + srgbToXyz[2][1]*g + srgbToXyz[2][2]*b,
+	}
+}
+
+// ArgbFromLab converts a color from CIE L*a*b* to ARGB
+func ArgbFromLab(l, a, b float64) uint32 {
+	fy := (l + 16.0) / 116.0
+	fx := a/500.0 + fy
+	fz := fy - b/200.0
+	x := labInvf(fx) * whitePointD65[0]
+	y := labInvf(fy) * whitePointD65[1]
+	z := labInvf(fz) * whitePointD65[2]
+	return ArgbFromXyz(x, y, z)
+}
+
+// LabFromArgb converts a color from ARGB to CIE L*a*b*
+func LabFromArgb(argb uint32) [3]float64 {
+	xyz := XyzFromArgb(argb)
+	fx := labF(xyz[0] / whitePointD65[0])
+	fy := labF(xyz[1] / whitePointD65[1])
+	fz := labF(xyz[2] / whitePointD65[2])
+	return [3]float64{116.0*fy - 16, 500.0 * (fx - fy), 200.0 * (fy - fz)}
+}
+
+// ArgbFromLstar returns the gray with the given L* (tone)
+func ArgbFromLstar(lstar float64) uint32 {
+	component := delinearized(YFromLstar(lstar))
+	return ArgbFromRgb(component, component, component)
+}
+
+// LstarFromArgb returns the L* (tone) of a color
+func LstarFromArgb(argb uint32) float64 {
+	return LstarFromY(XyzFromArgb(argb)[1])
+}
+
+// YFromLstar converts an L* value to relative luminance Y (0-100)
+func YFromLstar(lstar float64) float64 {
+	return 100.0 * labInvf((lstar+16.0)/116.0)
+}
+
+// LstarFromY converts relative luminance Y (0-100) to L*
+func LstarFromY(y float64) float64 {
+	return labF(y/100.0)*116.0 - 16.0
+}
+
+// linearized converts an 8-bit sRGB component to linear RGB (0-100)
+func linearized(component int) float64 {
+	normalized := float64(component) / 255.0
+	if normalized <= 0.040449936 {
+		return normalized / 12.92 * 100.0
+	}
+	return math.Pow((normalized+0.055)/1.055, 2.4) * 100.0
+}
+
+// delinearized converts a linear RGB component (0-100) to 8-bit sRGB
+func delinearized(component float64) int {
+	normalized := component / 100.0
+	var delinearized float64
+	if normalized <= 0.0031308 {
+		delinearized = normalized * 12.92
+	} else {
+		delinearized = 1.055*math.Pow(normalized, 1.0/2.4) - 0.055
+	}
+	return clampInt(0, 255, int(math.Round(delinearized*255.0)))
+}
+
+func labF(t float64) float64 {
+	const e = 216.0 / 24389.0
+	const kappa = 24389.0 / 27.0
+	if t > e {
+		return math.Cbrt(t)
+	}
+	return (kappa*t + 16) / 116
+}
+
+func labInvf(ft float64) float64 {
+	const e = 216.0 / 24389.0
+	const kappa = 24389.0 / 27.0
+	ft3 := ft * ft * ft
+	if ft3 > e {
+		return ft3
+	}
+	return (116*ft - 16) / kappa
+}
+
+// sanitizeDegrees wraps an angle into [0, 360)
+func sanitizeDegrees(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360.0)
+	if degrees < 0 {
+		degrees += 360.0
+	}
+	return degrees
+}
+
+// sanitizeDegreesInt wraps an integer angle into [0, 360)
+func sanitizeDegreesInt(degrees int) int {
+	degrees %= 360
+	if degrees < 0 {
+		degrees += 360
+	}
+	return degrees
+}
+
+// Mix blends two colors channel by channel in sRGB; amount 0 is from, 1 is to
+func Mix(from, to uint32, amount float64) uint32 {
+	amount = clampFloat(0, 1, amount)
+	channel := func(a, b int) int {
+		return clampInt(0, 255, int(math.Round(lerp(float64(a), float64(b), amount))))
+	}
+	return ArgbFromRgb(
+		channel(RedFromArgb(from), RedFromArgb(to)),
+		channel(GreenFromArgb(from), GreenFromArgb(to)),
+		channel(BlueFromArgb(from), BlueFromArgb(to)),
+	)
+}
+
+// differenceDegrees returns the shortest distance between two angles
+func differenceDegrees(a, b float64) float64 {
+	return 180.0 - math.Abs(math.Abs(a-b)-180.0)
+}
+
+func signum(x float64) float64 {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+func lerp(start, stop, amount float64) float64 {
+	return (1.0-amount)*start + amount*stop
+}
+
+func clampInt(min, max, value int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func clampFloat(min, max, value float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
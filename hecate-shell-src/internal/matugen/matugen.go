@@ -1,47 +1,143 @@
 package matugen
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 
-	"hecate-shell/internal/config"
+	"hecate-shell/internal/material"
+	"hecate-shell/internal/templates"
+
+	"github.com/BurntSushi/toml"
 )
 
 // Inspired from https://github.com/AvengeMedia/DankMaterialShell/blob/master/core/internal/matugen/matugen.go
 
-// RunMatugen executes matugen with a merged config (user config + HecateShell template)
-func RunMatugen(sourceType string, sourcePath string) error {
+// Options are the scheme settings passed through to matugen
+type Options struct {
+	Mode   string // "dark" or "light"
+	Scheme string // Variant name without the "scheme-" prefix, e.g. "tonal-spot"
+}
+
+// RunMatugen runs matugen on a source with the merged config, which renders the user's own
+// matugen templates, and returns every color role of the dark and light schemes as hex.
+// HecateShell's targets are rendered from these colors by the caller.
+func RunMatugen(sourceType string, sourcePath string, opts Options) (map[string]string, map[string]string, error) {
+	if _, err := exec.LookPath("matugen"); err != nil {
+		return nil, nil, fmt.Errorf("matugen not found in PATH (set \"theme.backend\" to \"native\" in config.json to use the built-in generator)")
+	}
+
 	// Create temporary merged config
-	configPath, cleanup, err := createMergedConfig()
+	configPath, collisions, cleanup, err := createMergedConfig()
 	if err != nil {
-		return fmt.Errorf("failed to create matugen config: %w", err)
+		return nil, nil, fmt.Errorf("failed to create matugen config: %w", err)
 	}
 	defer cleanup()
-
-	// Run matugen with the merged config
-	var cmd *exec.Cmd
-	if sourceType == "image" {
-		cmd = exec.Command("matugen", "image", sourcePath, "-t", "scheme-fidelity", "-m", "dark", "-c", configPath, "--continue-on-error")
-	} else if sourceType == "json" {
-		cmd = exec.Command("matugen", "json", sourcePath, "-t", "scheme-fidelity", "-m", "dark", "-c", configPath, "--continue-on-error")
-	} else {
-		return fmt.Errorf("unknown source type: %s", sourceType)
+	for _, collision := range collisions {
+		fmt.Printf("Warning: %s\n", collision)
 	}
 
-	cmd.Stdout = os.Stdout
+	// Run matugen with the merged config; the colors are printed as JSON on stdout
+	args, err := sourceArgs(sourceType, sourcePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd := exec.Command("matugen", append(args, "-c", configPath, "--json", "hex", "--continue-on-error")...)
 	cmd.Stderr = os.Stderr
 
-	return cmd.Run()
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseColors(output)
 }
 
-// createMergedConfig creates a temporary config that merges user's matugen config with HecateShell's template
-func createMergedConfig() (string, func(), error) {
+// sourceArgs builds the matugen arguments selecting the source, scheme and mode
+func sourceArgs(sourceType string, sourcePath string, opts Options) ([]string, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = "dark"
+	}
+	scheme := "scheme-fidelity"
+	if opts.Scheme != "" {
+		scheme = "scheme-" + opts.Scheme
+	}
+
+	var args []string
+	switch sourceType {
+	case "image":
+		args = []string{"image", sourcePath}
+	case "json":
+		args = []string{"json", sourcePath}
+	case "color":
+		args = []string{"color", "hex", sourcePath}
+	default:
+		return nil, fmt.Errorf("unknown source type: %s", sourceType)
+	}
+	return append(args, "-t", scheme, "-m", mode), nil
+}
+
+// parseColors reads the "colors" object of matugen's JSON output. Newer matugen versions
+// key it by role, then by mode; older ones by mode, then by role.
+func parseColors(output []byte) (map[string]string, map[string]string, error) {
+	// matugen may log around the JSON, so decode the first JSON object with colors and
+	// ignore anything after it
+	var result struct {
+		Colors map[string]map[string]string `json:"colors"`
+	}
+	err := errors.New("no JSON object")
+	for offset := 0; ; {
+		start := bytes.IndexByte(output[offset:], '{')
+		if start < 0 {
+			break
+		}
+		offset += start
+		if err = json.NewDecoder(bytes.NewReader(output[offset:])).Decode(&result); err == nil && result.Colors != nil {
+			break
+		}
+		offset++
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse matugen output: %w", err)
+	}
+
+	dark, light := make(map[string]string), make(map[string]string)
+	if result.Colors["dark"] != nil && result.Colors["light"] != nil {
+		for role, hex := range result.Colors["dark"] {
+			dark[role] = hex
+		}
+		for role, hex := range result.Colors["light"] {
+			light[role] = hex
+		}
+	} else {
+		for role, modes := range result.Colors {
+			dark[role], light[role] = modes["dark"], modes["light"]
+		}
+	}
+
+	if len(dark) == 0 {
+		return nil, nil, fmt.Errorf("no colors in matugen output")
+	}
+	return dark, light, nil
+}
+
+// createMergedConfig writes the merged config to a temporary file
+func createMergedConfig() (string, []string, func(), error) {
+	merged, collisions, err := MergedConfig()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
 	// Create temp file
 	tmpFile, err := os.CreateTemp("", "hecate-matugen-*.toml")
 	if err != nil {
-		return "", nil, err
+		return "", nil, nil, err
 	}
 	tmpPath := tmpFile.Name()
 
@@ -49,132 +145,151 @@ func createMergedConfig() (string, func(), error) {
 		os.Remove(tmpPath)
 	}
 
-	// Get user's matugen config (if it exists)
-	userConfigPath := filepath.Join(os.Getenv("HOME"), ".config/matugen/config.toml")
-	userConfig := ""
-	if data, err := os.ReadFile(userConfigPath); err == nil {
-		userConfig = string(data)
+	// Write to temp file
+	if _, err := tmpFile.WriteString(merged); err != nil {
+		tmpFile.Close()
+		cleanup()
+		return "", nil, nil, err
 	}
 
-	// Get HecateShell config dir
-	shellDir, err := config.GetConfigDir()
-	if err != nil {
+	if err := tmpFile.Close(); err != nil {
 		cleanup()
-		return "", nil, err
+		return "", nil, nil, err
 	}
 
-	// HecateShell template configs
-	// Some templates adapted from DankMaterialShell (https://github.com/AvengeMedia/DankMaterialShell)
-	homeDir := os.Getenv("HOME")
-	hecateTemplates := fmt.Sprintf(`
-# HecateShell core theme
-[templates.hecate]
-input_path = "%s/config/templates/hecate.json"
-output_path = "%s/theme.json"
-
-# Audio visualizer
-[templates.hecate_cava]
-input_path = "%s/config/templates/cava.ini"
-output_path = "%s/.config/cava/config"
-
-# Spotify
-[templates.hecate_spicetify]
-input_path = "%s/config/templates/spicetify.ini"
-output_path = "%s/.config/spicetify/Themes/text/color.ini"
-
-# Discord (Vencord)
-[templates.hecate_discord]
-input_path = "%s/config/templates/discord.css"
-output_path = "%s/.config/Vencord/themes/sys24.css"
-
-# Micro editor
-[templates.hecate_micro]
-input_path = "%s/config/templates/micro.micro"
-output_path = "%s/.config/micro/colorschemes/matugen.micro"
-
-# VSCode theme
-[templates.hecate_vscode]
-input_path = "%s/config/templates/vscode.json"
-output_path = "%s/.vscode/extensions/hecate-theme/themes/hecate-dark.json"
-
-# Niri compositor colors
-[templates.hecate_niri]
-input_path = "%s/config/templates/niri-colors.kdl"
-output_path = "%s/.config/niri/hecate-colors.generated.kdl"
-
-# Firefox (pywalfox)
-[templates.hecate_pywalfox]
-input_path = "%s/config/templates/pywalfox.json"
-output_path = "%s/.cache/wal/colors.json"
-
-# Kitty terminal
-[templates.hecate_kitty]
-input_path = "%s/config/templates/kitty.conf"
-output_path = "%s/.config/kitty/hecate-colors.conf"
-
-# Kitty tabs
-[templates.hecate_kitty_tabs]
-input_path = "%s/config/templates/kitty-tabs.conf"
-output_path = "%s/.config/kitty/hecate-tabs.conf"
-
-# Alacritty terminal
-[templates.hecate_alacritty]
-input_path = "%s/config/templates/alacritty.toml"
-output_path = "%s/.config/alacritty/hecate-colors.toml"
-
-# KDE color scheme
-[templates.hecate_kde]
-input_path = "%s/config/templates/kcolorscheme.colors"
-output_path = "%s/.local/share/color-schemes/HecateShell.colors"
-
-# Qt5ct/Qt6ct colors
-[templates.hecate_qt]
-input_path = "%s/config/templates/qt5ct-colors.conf"
-output_path = "%s/.config/qt5ct/colors/HecateShell.conf"
-
-# GTK colors
-[templates.hecate_gtk]
-input_path = "%s/config/templates/gtk-colors.css"
-output_path = "%s/.config/gtk-4.0/gtk-colors.css"
-
-[templates.hecate_gtk3]
-input_path = "%s/config/templates/gtk-colors.css"
-output_path = "%s/.config/gtk-3.0/gtk.css"
-
-# Neovim colorscheme (lazy.nvim plugin)
-[templates.hecate_nvim]
-input_path = "%s/config/templates/nvim.lua"
-output_path = "%s/.config/nvim/lua/plugins/hecate-colors.lua"
-`, shellDir, shellDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir,
-		shellDir, homeDir)
-
-	// Merge configs: user config + HecateShell templates
-	mergedConfig := userConfig + "\n" + hecateTemplates
+	return tmpPath, collisions, cleanup, nil
+}
 
-	// Write to temp file
-	if _, err := tmpFile.WriteString(mergedConfig); err != nil {
-		cleanup()
+// GetUserConfigFile returns the path to the user's own matugen config
+func GetUserConfigFile() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "matugen", "config.toml"), nil
+}
+
+// MergedConfig parses the user's matugen config and declares HecateShell's semantic colors
+// in it. HecateShell renders its own targets, so a user template with the name and output
+// of one is left out, and every output path collision is returned as a human-readable
+// message. HecateShell's targets are listed as comments at the end.
+func MergedConfig() (string, []string, error) {
+	userConfigPath, err := GetUserConfigFile()
+	if err != nil {
 		return "", nil, err
 	}
 
-	if err := tmpFile.Close(); err != nil {
-		cleanup()
+	// Get user's matugen config (if it exists)
+	merged := make(map[string]interface{})
+	if _, err := toml.DecodeFile(userConfigPath, &merged); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", nil, fmt.Errorf("failed to parse %s: %w", userConfigPath, err)
+	}
+
+	userTemplates := make(map[string]interface{})
+	if section, ok := merged["templates"]; ok {
+		table, ok := section.(map[string]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("invalid %s: templates must be a table", userConfigPath)
+		}
+		userTemplates = table
+	}
+
+	// The merged file lives in a temp dir, so anchor the user's relative paths to their config dir
+	userDir := filepath.Dir(userConfigPath)
+	outputs := make(map[string]string)
+	for name, entry := range userTemplates {
+		table, ok := entry.(map[string]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("invalid %s: templates.%s must be a table", userConfigPath, name)
+		}
+		for _, key := range []string{"input_path", "output_path"} {
+			if path, ok := table[key].(string); ok && path != "" && !filepath.IsAbs(path) && !strings.HasPrefix(path, "~") {
+				table[key] = filepath.Join(userDir, path)
+			}
+		}
+		if output, ok := table["output_path"].(string); ok {
+			outputs[expandHome(output)] = name
+		}
+	}
+
+	// HecateShell's targets are rendered after matugen, so theirs win
+	targets, err := templates.Targets()
+	if err != nil {
 		return "", nil, err
 	}
 
-	return tmpPath, cleanup, nil
+	var collisions []string
+	for _, target := range targets {
+		name, ok := outputs[target.Output]
+		switch {
+		case !ok:
+		case name == target.Name:
+			delete(userTemplates, name)
+			collisions = append(collisions, fmt.Sprintf("template %q in %s is replaced by HecateShell's template of the same name", name, userConfigPath))
+		default:
+			collisions = append(collisions, fmt.Sprintf("template %q in %s and HecateShell's %q both write %s", name, userConfigPath, target.Name, target.Output))
+		}
+	}
+
+	merged["templates"] = userTemplates
+	sort.Strings(collisions)
+
+	if err := addSemanticColors(merged); err != nil {
+		return "", nil, fmt.Errorf("invalid %s: %w", userConfigPath, err)
+	}
+
+	var b strings.Builder
+	if err := toml.NewEncoder(&b).Encode(merged); err != nil {
+		return "", nil, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+
+	// Comments, so matugen ignores them
+	b.WriteString("\n# Rendered by HecateShell from the colors matugen generates:\n")
+	for _, target := range targets {
+		fmt.Fprintf(&b, "# %s -> %s\n", target.Name, target.Output)
+	}
+	return b.String(), collisions, nil
+}
+
+// addSemanticColors declares HecateShell's semantic colors as matugen custom colors, so
+// templates get the same success/warning/info roles as from the built-in engine. Custom
+// colors the user already declared under those names are kept.
+func addSemanticColors(merged map[string]interface{}) error {
+	section, ok := merged["config"].(map[string]interface{})
+	if !ok {
+		if _, exists := merged["config"]; exists {
+			return fmt.Errorf("config must be a table")
+		}
+		section = make(map[string]interface{})
+		merged["config"] = section
+	}
+
+	customColors, ok := section["custom_colors"].(map[string]interface{})
+	if !ok {
+		if _, exists := section["custom_colors"]; exists {
+			return fmt.Errorf("config.custom_colors must be a table")
+		}
+		customColors = make(map[string]interface{})
+		section["custom_colors"] = customColors
+	}
+
+	for _, c := range material.SemanticColors {
+		if _, ok := customColors[c.Name]; !ok {
+			customColors[c.Name] = map[string]interface{}{
+				"color": material.HexFromArgb(c.Seed),
+				"blend": true,
+			}
+		}
+	}
+	return nil
+}
+
+// expandHome expands a leading "~/" to the user's home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, rest)
+		}
+	}
+	return path
 }
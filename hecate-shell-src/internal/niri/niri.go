@@ -2,28 +2,19 @@ package niri
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
-	"hecate-shell/internal/config"
+	"hecate-shell/internal/theme"
 )
 
-// Theme represents the theme.json structure (partial)
-type Theme struct {
-	Primary string `json:"primary"`
-	Outline string `json:"outline"`
-	Error   string `json:"error"`
-	Shadow  string `json:"shadow"`
-}
-
 // UpdateNiriColors reads the theme.json and updates niri config colors
 func UpdateNiriColors() error {
 	// Read theme.json
-	theme, err := readTheme()
+	colors, err := theme.LoadTheme()
 	if err != nil {
 		return fmt.Errorf("failed to read theme: %w", err)
 	}
@@ -73,18 +64,18 @@ func UpdateNiriColors() error {
 		if inFocusRing || inBorder {
 			indent := getIndent(line)
 			if activeColorRe.MatchString(line) {
-				line = fmt.Sprintf(`%sactive-color "%s"`, indent, theme.Primary)
+				line = fmt.Sprintf(`%sactive-color "%s"`, indent, colors.Primary)
 			} else if inactiveColorRe.MatchString(line) {
-				line = fmt.Sprintf(`%sinactive-color "%s"`, indent, theme.Outline)
+				line = fmt.Sprintf(`%sinactive-color "%s"`, indent, colors.Outline)
 			} else if urgentColorRe.MatchString(line) {
-				line = fmt.Sprintf(`%surgent-color "%s"`, indent, theme.Error)
+				line = fmt.Sprintf(`%surgent-color "%s"`, indent, colors.Error)
 			}
 		}
 
 		// Replace shadow color (primary color with 80 alpha for transparency)
 		if inShadow && shadowColorRe.MatchString(line) {
 			indent := getIndent(line)
-			line = fmt.Sprintf(`%scolor "%s80"`, indent, theme.Primary)
+			line = fmt.Sprintf(`%scolor "%s80"`, indent, colors.Primary)
 		}
 
 		lines = append(lines, line)
@@ -104,27 +95,6 @@ func UpdateNiriColors() error {
 	return nil
 }
 
-// readTheme reads the theme.json file
-func readTheme() (*Theme, error) {
-	shellDir, err := config.GetConfigDir()
-	if err != nil {
-		return nil, err
-	}
-
-	themePath := filepath.Join(shellDir, "theme.json")
-	data, err := os.ReadFile(themePath)
-	if err != nil {
-		return nil, err
-	}
-
-	var theme Theme
-	if err := json.Unmarshal(data, &theme); err != nil {
-		return nil, err
-	}
-
-	return &theme, nil
-}
-
 // getIndent extracts the leading whitespace from a line
 func getIndent(line string) string {
 	for i, c := range line {
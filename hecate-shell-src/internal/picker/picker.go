@@ -0,0 +1,374 @@
+package picker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/installer/anim"
+	"hecate-shell/internal/installer/components"
+	"hecate-shell/internal/installer/styles"
+	"hecate-shell/internal/material"
+	"hecate-shell/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Choice kinds
+const (
+	KindWallpaper = "wallpaper"
+	KindTheme     = "theme"
+)
+
+// Choice is what the user picked
+type Choice struct {
+	Kind   string           // KindWallpaper or KindTheme
+	Path   string           // Wallpaper to set and generate from
+	Name   string           // Saved theme to apply
+	Mode   string           // Mode for wallpapers: dark, light or auto
+	Scheme material.Variant // Scheme variant for wallpapers
+}
+
+// wallpaperExtensions are the images listed from the wallpapers directory
+var wallpaperExtensions = []string{".jpg", ".jpeg", ".png", ".webp", ".gif"}
+
+// modes in the order of the mode selector
+var modes = []string{theme.ModeDark, theme.ModeLight, theme.ModeAuto}
+
+// visibleItems bounds how many list entries are shown at once
+const visibleItems = 14
+
+// tickMsg is sent every frame for animations
+type tickMsg time.Time
+
+// previewMsg carries a preview generated in the background
+type previewMsg struct {
+	key     string
+	preview theme.Preview
+	err     error
+}
+
+// item is a browsable wallpaper or saved theme
+type item struct {
+	kind  string
+	path  string // Wallpaper path
+	name  string // Saved theme name
+	label string
+}
+
+// Model is the theme picker
+type Model struct {
+	items   []item
+	list    *components.Selector
+	modeSel *components.Selector
+	variant int // Index into material.Variants
+
+	previews map[string]theme.Preview
+	errors   map[string]error
+	pending  map[string]bool
+	slide    *anim.Spring // Slides the preview in when it changes
+	shown    string       // Key of the preview currently shown
+
+	width, height int
+	choice        *Choice
+}
+
+// New creates a picker over saved themes and the wallpapers directory
+func New(settings config.ThemeSettings) (Model, error) {
+	items, err := loadItems()
+	if err != nil {
+		return Model{}, err
+	}
+	if len(items) == 0 {
+		return Model{}, fmt.Errorf("no saved themes or wallpapers to pick from")
+	}
+
+	options := make([]components.Option, len(items))
+	for i, it := range items {
+		options[i] = components.Option{Label: it.label, Value: it.kind}
+	}
+
+	modeOptions := make([]components.Option, len(modes))
+	for i, mode := range modes {
+		modeOptions[i] = components.Option{Label: strings.ToUpper(mode[:1]) + mode[1:], Value: mode}
+	}
+	modeSel := components.NewSelector(modeOptions)
+	for i, mode := range modes {
+		if mode == settings.Mode {
+			modeSel.SetSelected(i)
+		}
+	}
+	modeSel.Blur()
+
+	variant := 0
+	for i, v := range material.Variants {
+		if string(v) == settings.Scheme {
+			variant = i
+		}
+	}
+
+	slide := anim.NewBouncySpring()
+	return Model{
+		items:    items,
+		list:     components.NewSelector(options),
+		modeSel:  modeSel,
+		variant:  variant,
+		previews: make(map[string]theme.Preview),
+		errors:   make(map[string]error),
+		pending:  make(map[string]bool),
+		slide:    slide,
+	}, nil
+}
+
+// Run shows the picker and returns the user's choice; ok is false if they quit
+func Run(settings config.ThemeSettings) (Choice, bool, error) {
+	model, err := New(settings)
+	if err != nil {
+		return Choice{}, false, err
+	}
+
+	final, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	if err != nil {
+		return Choice{}, false, err
+	}
+
+	m := final.(Model)
+	if m.choice == nil {
+		return Choice{}, false, nil
+	}
+	return *m.choice, true, nil
+}
+
+// loadItems lists wallpapers first, then saved themes
+func loadItems() ([]item, error) {
+	shellDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []item
+	entries, _ := os.ReadDir(filepath.Join(shellDir, "wallpapers"))
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		for _, known := range wallpaperExtensions {
+			if ext == known && !entry.IsDir() {
+				items = append(items, item{
+					kind:  KindWallpaper,
+					path:  filepath.Join(shellDir, "wallpapers", entry.Name()),
+					label: entry.Name(),
+				})
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].label < items[j].label })
+
+	snapshots, err := theme.ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range snapshots {
+		items = append(items, item{kind: KindTheme, name: s.Name, label: s.Name + " (saved)"})
+	}
+	return items, nil
+}
+
+// Init starts the animation loop and the first preview
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(tick(), m.requestPreview())
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(time.Second/60, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// Update handles messages
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case tickMsg:
+		m.list.Update()
+		m.modeSel.Update()
+		m.slide.Update()
+		m.syncSlide()
+		return m, tick()
+
+	case previewMsg:
+		delete(m.pending, msg.key)
+		if msg.err != nil {
+			m.errors[msg.key] = msg.err
+		} else {
+			m.previews[msg.key] = msg.preview
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		m.list.Prev()
+	case "down", "j":
+		m.list.Next()
+	case "tab", "m":
+		m.modeSel.Next()
+	case "]", "l", "right":
+		m.variant = (m.variant + 1) % len(material.Variants)
+	case "[", "h", "left":
+		m.variant = (m.variant + len(material.Variants) - 1) % len(material.Variants)
+	case "enter":
+		it := m.selected()
+		m.choice = &Choice{
+			Kind:   it.kind,
+			Path:   it.path,
+			Name:   it.name,
+			Mode:   m.mode(),
+			Scheme: material.Variants[m.variant],
+		}
+		return m, tea.Quit
+	default:
+		return m, nil
+	}
+	return m, m.requestPreview()
+}
+
+func (m Model) selected() item {
+	return m.items[m.list.SelectedIndex()]
+}
+
+func (m Model) mode() string {
+	return m.modeSel.Selected().Value
+}
+
+// previewKey identifies a preview; saved themes don't depend on mode or scheme
+func (m Model) previewKey() string {
+	it := m.selected()
+	if it.kind == KindTheme {
+		return "theme:" + it.name
+	}
+	return fmt.Sprintf("wallpaper:%s:%s:%s", it.path, m.mode(), material.Variants[m.variant])
+}
+
+// requestPreview generates the selected preview in the background unless it is cached
+func (m Model) requestPreview() tea.Cmd {
+	key := m.previewKey()
+	if _, ok := m.previews[key]; ok || m.pending[key] || m.errors[key] != nil {
+		return nil
+	}
+	m.pending[key] = true
+
+	it := m.selected()
+	opts := theme.Options{Mode: m.mode(), Scheme: material.Variants[m.variant]}
+	return func() tea.Msg {
+		var preview theme.Preview
+		var err error
+		if it.kind == KindTheme {
+			preview, err = theme.PreviewSnapshot(it.name)
+		} else {
+			preview, err = theme.PreviewImage(it.path, opts)
+		}
+		return previewMsg{key: key, preview: preview, err: err}
+	}
+}
+
+// View renders the picker
+func (m Model) View() string {
+	title := styles.Title.Render("Theme picker")
+
+	list := m.viewList()
+	preview := m.viewPreview()
+	body := lipgloss.JoinHorizontal(lipgloss.Top,
+		styles.Card.Width(32).Render(list),
+		"  ",
+		preview,
+	)
+
+	variant := styles.DimText.Render("◂ ") +
+		styles.AccentText.Bold(true).Render(string(material.Variants[m.variant])) +
+		styles.DimText.Render(" ▸")
+	controls := lipgloss.JoinHorizontal(lipgloss.Center,
+		styles.NormalText.Render("Mode  "), m.modeSel.View(),
+		styles.NormalText.Render("   Scheme  "), variant,
+	)
+	if m.selected().kind == KindTheme {
+		controls = styles.DimText.Render("Saved themes are applied as saved")
+	}
+
+	help := styles.Help.Render("↑/↓ browse • tab mode • ←/→ scheme • enter apply • q quit")
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(
+		lipgloss.JoinVertical(lipgloss.Left, title, "", body, "", controls, help),
+	)
+}
+
+// viewList renders the list, scrolled so the cursor stays visible
+func (m Model) viewList() string {
+	lines := strings.Split(m.list.ViewVertical(), "\n")
+	if len(lines) <= visibleItems {
+		return strings.Join(lines, "\n")
+	}
+
+	start := m.list.SelectedIndex() - visibleItems/2
+	start = max(0, min(start, len(lines)-visibleItems))
+	return strings.Join(lines[start:start+visibleItems], "\n")
+}
+
+// syncSlide starts the slide-in animation when a new preview becomes visible
+func (m *Model) syncSlide() {
+	key := m.previewKey()
+	if _, ok := m.previews[key]; ok && key != m.shown {
+		m.shown = key
+		m.slide.SetPos(4)
+		m.slide.SetTarget(0)
+	}
+}
+
+// viewPreview renders swatches for the selected item
+func (m Model) viewPreview() string {
+	key := m.previewKey()
+	if err := m.errors[key]; err != nil {
+		return styles.ErrorText.Render("Preview failed: " + err.Error())
+	}
+	preview, ok := m.previews[key]
+	if !ok {
+		return styles.DimText.Render("Generating preview...")
+	}
+
+	offset := max(0, int(m.slide.Pos()+0.5))
+
+	var rows []string
+	for _, role := range preview.Roles {
+		hex := material.HexFromArgb(role.Color)
+		swatch := lipgloss.NewStyle().Background(lipgloss.Color(hex)).Render("    ")
+		rows = append(rows, swatch+" "+styles.NormalText.Render(role.Name)+" "+styles.DimText.Render(hex))
+	}
+
+	if len(preview.ANSI) == 16 {
+		rows = append(rows, "")
+		for row := 0; row < 2; row++ {
+			var cells string
+			for _, color := range preview.ANSI[row*8 : row*8+8] {
+				cells += lipgloss.NewStyle().Background(lipgloss.Color(material.HexFromArgb(color))).Render("   ")
+			}
+			rows = append(rows, cells)
+		}
+	}
+
+	return lipgloss.NewStyle().PaddingLeft(offset).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
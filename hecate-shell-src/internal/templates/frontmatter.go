@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// frontMatterDelimiter opens and closes a template's front matter
+const frontMatterDelimiter = "---"
+
+// parseFrontMatter splits a user template into its front matter and body. Front matter is
+// a block of "key: value" lines between two "---" lines at the very top of the file,
+// using the same keys as a manifest entry:
+//
+//	---
+//	name: foot
+//	output: ~/.config/foot/colors.ini
+//	post_hook: pkill -USR1 foot
+//	---
+//
+// Templates without front matter are returned unchanged with ok set to false.
+func parseFrontMatter(src string) (entry manifestEntry, body string, ok bool, err error) {
+	first, rest, found := strings.Cut(src, "\n")
+	if !found || strings.TrimSpace(first) != frontMatterDelimiter {
+		return entry, src, false, nil
+	}
+
+	lineNum := 1
+	for {
+		var line string
+		line, rest, found = strings.Cut(rest, "\n")
+		lineNum++
+		if strings.TrimSpace(line) == frontMatterDelimiter {
+			return entry, rest, true, nil
+		}
+		if !found {
+			return entry, src, false, fmt.Errorf("front matter is not closed with %s", frontMatterDelimiter)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return entry, src, false, fmt.Errorf("line %d: expected key: value", lineNum)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch strings.TrimSpace(key) {
+		case "name":
+			entry.Name = value
+		case "description":
+			entry.Description = value
+		case "output":
+			entry.Output = value
+		case "post_hook":
+			entry.PostHook = value
+		case "enabled":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return entry, src, false, fmt.Errorf("line %d: enabled must be true or false", lineNum)
+			}
+			entry.Enabled = &enabled
+		default:
+			return entry, src, false, fmt.Errorf("line %d: unknown key %q", lineNum, strings.TrimSpace(key))
+		}
+	}
+}
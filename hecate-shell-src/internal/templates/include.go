@@ -0,0 +1,200 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Markers around a Block in the config it is kept in
+const (
+	blockStart = "# >>> HecateShell colors (generated, edits are overwritten) >>>"
+	blockEnd   = "# <<< HecateShell colors <<<"
+)
+
+// Ensure adds the include line to the config unless it is already there. A commented-out
+// copy counts as present, so users can opt out by commenting the line.
+func (inc Include) Ensure() error {
+	data, err := os.ReadFile(inc.Config)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", inc.Config, err)
+	}
+	if data == nil && inc.Optional {
+		return nil
+	}
+	content := string(data)
+	lines := strings.Split(content, "\n")
+	if data == nil {
+		lines = []string{""}
+	}
+
+	line := inc.Line
+	at := 0
+	if inc.Before != "" {
+		before := regexp.MustCompile(inc.Before)
+		at = -1
+		for i, l := range lines {
+			if m := before.FindStringSubmatchIndex(l); m != nil {
+				line = string(before.ExpandString(nil, inc.Line, l, m))
+				at = i
+				break
+			}
+		}
+		if at < 0 {
+			if strings.Contains(content, inc.Line) {
+				return nil
+			}
+			return fmt.Errorf("could not find where to load the generated colors in %s (no line matches %s)", inc.Config, inc.Before)
+		}
+	}
+
+	if strings.Contains(content, line) {
+		return nil
+	}
+
+	replaced := false
+	if inc.Replace != "" {
+		replace := regexp.MustCompile(inc.Replace)
+		for i, l := range lines {
+			if replace.MatchString(l) {
+				lines[i] = line
+				replaced = true
+				break
+			}
+		}
+	}
+	if !replaced {
+		lines = append(lines[:at], append([]string{line}, lines[at:]...)...)
+	}
+
+	if err := writeConfig(inc.Config, lines); err != nil {
+		return err
+	}
+	fmt.Printf("Added %s to %s\n", line, inc.Config)
+	return nil
+}
+
+// Ensure copies rendered output into the config between marker comments, replacing the
+// previous copy. With a parent, the first line of the output is the parent key: if the
+// config already has that key, only the lines below it are inserted under it, re-indented
+// to match the config.
+func (b Block) Ensure(rendered string) error {
+	data, err := os.ReadFile(b.Config)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", b.Config, err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if data == nil {
+		lines = nil
+	}
+	added := true
+	at := len(lines)
+	if start, end := markedBlock(lines); start >= 0 {
+		lines = append(lines[:start], lines[end+1:]...)
+		at = start
+		added = false
+	}
+
+	block := strings.Split(strings.TrimRight(rendered, "\n"), "\n")
+	for len(block) > 0 && (strings.TrimSpace(block[0]) == "" || strings.HasPrefix(block[0], "#")) {
+		block = block[1:] // Drop the template's header comment
+	}
+
+	if b.Parent != "" && len(block) > 1 && block[0] == b.Parent {
+		for i, l := range lines {
+			if l != b.Parent {
+				continue
+			}
+			if key := nestedKey(lines[i+1:], block[1]); key != "" {
+				return fmt.Errorf("%s already sets %s.%s; remove it to use the generated colors", b.Config, strings.TrimSuffix(b.Parent, ":"), key)
+			}
+			block = reindent(block[1:], indentUnit(block[1:]), indentUnit(lines[i+1:]))
+			at = i + 1
+			break
+		}
+	}
+
+	marked := append(append([]string{blockStart}, block...), blockEnd)
+	lines = append(lines[:at], append(marked, lines[at:]...)...)
+	if err := writeConfig(b.Config, append(lines, "")); err != nil {
+		return err
+	}
+	if added {
+		fmt.Printf("Added HecateShell colors to %s\n", b.Config)
+	}
+	return nil
+}
+
+// markedBlock returns the line indexes of the block markers, or -1 if there is no block
+func markedBlock(lines []string) (int, int) {
+	start := -1
+	for i, l := range lines {
+		switch strings.TrimSpace(l) {
+		case blockStart:
+			start = i
+		case blockEnd:
+			if start >= 0 {
+				return start, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// nestedKey returns the key of line if the indented lines below a parent already have it
+func nestedKey(children []string, line string) string {
+	key, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+	for _, l := range children {
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(l, " ") && !strings.HasPrefix(l, "\t") {
+			break // Next top-level key
+		}
+		if k, _, _ := strings.Cut(trimmed, ":"); k == key {
+			return key
+		}
+	}
+	return ""
+}
+
+// indentUnit returns the indentation of the first indented line, defaulting to two spaces
+func indentUnit(lines []string) int {
+	for _, l := range lines {
+		if trimmed := strings.TrimLeft(l, " "); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			if n := len(l) - len(trimmed); n > 0 {
+				return n
+			}
+			break
+		}
+	}
+	return 2
+}
+
+// reindent changes the indentation unit of lines from one width to another
+func reindent(lines []string, from, to int) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		trimmed := strings.TrimLeft(l, " ")
+		depth := (len(l) - len(trimmed)) / from
+		out[i] = strings.Repeat(" ", depth*to) + trimmed
+	}
+	return out
+}
+
+// writeConfig writes an application config edited line by line
+func writeConfig(path string, lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	return nil
+}
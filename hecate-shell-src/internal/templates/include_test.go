@@ -0,0 +1,218 @@
+package templates
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readConfig returns a test config, or "<missing>" if it doesn't exist
+func readConfig(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "<missing>"
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestIncludeEnsure(t *testing.T) {
+	const wezLine = `${1}.colors = require("hecate-colors")`
+	tests := []struct {
+		name    string
+		config  string // Empty for a missing config
+		include Include
+		want    string
+	}{
+		{
+			name:    "creates a missing config",
+			include: Include{Line: "include=~/.config/foot/hecate-colors.ini"},
+			want:    "include=~/.config/foot/hecate-colors.ini\n",
+		},
+		{
+			name:    "leaves a missing optional config alone",
+			include: Include{Line: "include=colors.ini", Optional: true},
+			want:    "<missing>",
+		},
+		{
+			name:    "inserts at the top",
+			config:  "font=monospace:size=11\n\n[main]\nterm=foot\n",
+			include: Include{Line: "include=~/.config/foot/hecate-colors.ini"},
+			want:    "include=~/.config/foot/hecate-colors.ini\nfont=monospace:size=11\n\n[main]\nterm=foot\n",
+		},
+		{
+			name:    "inserts before a regexp with its groups",
+			config:  "local wezterm = require(\"wezterm\")\nlocal cfg = wezterm.config_builder()\ncfg.font_size = 11\nreturn cfg\n",
+			include: Include{Line: wezLine, Before: `^return\s+(\w+)\s*$`},
+			want:    "local wezterm = require(\"wezterm\")\nlocal cfg = wezterm.config_builder()\ncfg.font_size = 11\ncfg.colors = require(\"hecate-colors\")\nreturn cfg\n",
+		},
+		{
+			name:    "replaces a regexp match",
+			config:  "font-size = 12\ntheme = Dracula\nwindow-padding-x = 4\n",
+			include: Include{Line: "theme = HecateShell", Replace: `^theme\s*=`},
+			want:    "font-size = 12\ntheme = HecateShell\nwindow-padding-x = 4\n",
+		},
+		{
+			name:    "inserts at the top when nothing matches replace",
+			config:  "font-size = 12\n",
+			include: Include{Line: "theme = HecateShell", Replace: `^theme\s*=`},
+			want:    "theme = HecateShell\nfont-size = 12\n",
+		},
+		{
+			name:    "keeps a present line",
+			config:  "set -g mouse on\nsource-file ~/.config/tmux/hecate-colors.conf\n",
+			include: Include{Line: "source-file ~/.config/tmux/hecate-colors.conf"},
+			want:    "set -g mouse on\nsource-file ~/.config/tmux/hecate-colors.conf\n",
+		},
+		{
+			name:    "keeps a commented-out line",
+			config:  "# include=colors.ini\nfont=mono\n",
+			include: Include{Line: "include=colors.ini"},
+			want:    "# include=colors.ini\nfont=mono\n",
+		},
+		{
+			name:    "keeps an expanded line when before no longer matches",
+			config:  "local cfg = {}\ncfg.colors = require(\"hecate-colors\")\nreturn {}\n",
+			include: Include{Line: `cfg.colors = require("hecate-colors")`, Before: `^return\s+(\w+)\s*$`},
+			want:    "local cfg = {}\ncfg.colors = require(\"hecate-colors\")\nreturn {}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config")
+		if tt.config != "" {
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		inc := tt.include
+		inc.Config = path
+
+		// The second run must leave the config as the first one did
+		for run := 1; run <= 2; run++ {
+			if err := inc.Ensure(); err != nil {
+				t.Errorf("%s: run %d: %v", tt.name, run, err)
+				break
+			}
+			if got := readConfig(t, path); got != tt.want {
+				t.Errorf("%s: run %d: got\n%s\nwant\n%s", tt.name, run, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestIncludeEnsureBeforeNotFound(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wezterm.lua")
+	config := "return {\n  font_size = 11,\n}\n"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inc := Include{Config: path, Line: `${1}.colors = require("hecate-colors")`, Before: `^return\s+(\w+)\s*$`}
+	if err := inc.Ensure(); err == nil {
+		t.Error("Ensure succeeded without a line matching before")
+	}
+	if got := readConfig(t, path); got != config {
+		t.Errorf("config changed to\n%s", got)
+	}
+}
+
+func TestBlockEnsure(t *testing.T) {
+	const lazygit = "# lazygit colors - generated by HecateShell\n\ngui:\n  theme:\n    activeBorderColor:\n      - '#aabbcc'\n      - bold\n"
+	marked := func(lines ...string) string {
+		return blockStart + "\n" + strings.Join(lines, "\n") + "\n" + blockEnd
+	}
+
+	tests := []struct {
+		name     string
+		config   string // Empty for a missing config
+		parent   string
+		rendered string
+		want     string
+	}{
+		{
+			name:     "creates a missing config",
+			rendered: "# header\n[palettes.hecate]\nprimary = '#aabbcc'\n",
+			want:     marked("[palettes.hecate]", "primary = '#aabbcc'") + "\n",
+		},
+		{
+			name:     "appends to a config",
+			config:   "format = '$all'\n",
+			rendered: "[palettes.hecate]\nprimary = '#aabbcc'\n",
+			want:     "format = '$all'\n" + marked("[palettes.hecate]", "primary = '#aabbcc'") + "\n",
+		},
+		{
+			name:     "replaces a marked block in place",
+			config:   "format = '$all'\n" + marked("[palettes.hecate]", "primary = '#000000'") + "\n[character]\nsymbol = '>'\n",
+			rendered: "[palettes.hecate]\nprimary = '#aabbcc'\n",
+			want:     "format = '$all'\n" + marked("[palettes.hecate]", "primary = '#aabbcc'") + "\n[character]\nsymbol = '>'\n",
+		},
+		{
+			name:     "adds the parent key when the config has none",
+			config:   "git:\n  paging:\n    pager: delta\n",
+			parent:   "gui:",
+			rendered: lazygit,
+			want:     "git:\n  paging:\n    pager: delta\n" + marked("gui:", "  theme:", "    activeBorderColor:", "      - '#aabbcc'", "      - bold") + "\n",
+		},
+		{
+			name:     "nests under a parent with 2-space children",
+			config:   "gui:\n  language: en\n  nerdFontsVersion: \"3\"\ngit:\n  autoFetch: false\n",
+			parent:   "gui:",
+			rendered: lazygit,
+			want:     "gui:\n" + marked("  theme:", "    activeBorderColor:", "      - '#aabbcc'", "      - bold") + "\n  language: en\n  nerdFontsVersion: \"3\"\ngit:\n  autoFetch: false\n",
+		},
+		{
+			name:     "nests under a parent with 4-space children",
+			config:   "gui:\n    language: en\ngit:\n    autoFetch: false\n",
+			parent:   "gui:",
+			rendered: lazygit,
+			want:     "gui:\n" + marked("    theme:", "        activeBorderColor:", "            - '#aabbcc'", "            - bold") + "\n    language: en\ngit:\n    autoFetch: false\n",
+		},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config")
+		if tt.config != "" {
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		block := Block{Config: path, Parent: tt.parent}
+
+		// The second run must leave the config as the first one did
+		for run := 1; run <= 2; run++ {
+			if err := block.Ensure(tt.rendered); err != nil {
+				t.Errorf("%s: run %d: %v", tt.name, run, err)
+				break
+			}
+			if got := readConfig(t, path); got != tt.want {
+				t.Errorf("%s: run %d: got\n%s\nwant\n%s", tt.name, run, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestBlockEnsureParentKeyTaken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	config := "gui:\n  theme:\n    activeBorderColor:\n      - green\n"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	block := Block{Config: path, Parent: "gui:"}
+	err := block.Ensure("gui:\n  theme:\n    activeBorderColor:\n      - '#aabbcc'\n")
+	if err == nil || !strings.Contains(err.Error(), "gui.theme") {
+		t.Errorf("Ensure = %v, want an error naming gui.theme", err)
+	}
+	if got := readConfig(t, path); got != config {
+		t.Errorf("config changed to\n%s", got)
+	}
+}
@@ -0,0 +1,359 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"hecate-shell/internal/material"
+)
+
+// Data is the color data a template is rendered against
+type Data struct {
+	Image  string            // Source wallpaper, exposed as {{image}}
+	Dark   map[string]uint32 // Roles for the dark scheme ({{colors.<role>.dark.<format>}})
+	Light  map[string]uint32 // Roles for the light scheme ({{colors.<role>.light.<format>}})
+	IsDark bool              // Whether the "default" variant resolves to Dark
+}
+
+// Default returns the roles of the scheme the "default" variant resolves to
+func (d Data) Default() map[string]uint32 {
+	if d.IsDark {
+		return d.Dark
+	}
+	return d.Light
+}
+
+// expressionRe matches matugen-style {{ ... }} expressions
+var expressionRe = regexp.MustCompile(`\{\{\s*(.*?)\s*\}\}`)
+
+// Render expands matugen-style expressions such as {{colors.primary.default.hex}}.
+// Every problem is reported with its line number.
+func Render(src string, data Data) (string, error) {
+	return render(src, 1, data)
+}
+
+// RenderTemplate renders a user template, skipping its front matter if it has any
+func RenderTemplate(src string, data Data) (string, error) {
+	_, body, _, err := parseFrontMatter(src)
+	if err != nil {
+		return "", err
+	}
+	firstLine := 1 + strings.Count(src[:len(src)-len(body)], "\n")
+	return render(body, firstLine, data)
+}
+
+// render expands the expressions in src, which starts at line firstLine of its file
+func render(src string, firstLine int, data Data) (string, error) {
+	lineAt := func(offset int) int {
+		return firstLine + strings.Count(src[:offset], "\n")
+	}
+
+	var out strings.Builder
+	var errs []error
+	checkUnclosed := func(start, end int) {
+		if i := strings.Index(src[start:end], "{{"); i >= 0 {
+			errs = append(errs, fmt.Errorf("line %d: unclosed {{", lineAt(start+i)))
+		}
+	}
+
+	last := 0
+	for _, m := range expressionRe.FindAllStringSubmatchIndex(src, -1) {
+		checkUnclosed(last, m[0])
+		out.WriteString(src[last:m[0]])
+
+		value, err := evaluate(src[m[2]:m[3]], data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", lineAt(m[0]), err))
+			value = src[m[0]:m[1]]
+		}
+		out.WriteString(value)
+		last = m[1]
+	}
+	checkUnclosed(last, len(src))
+	out.WriteString(src[last:])
+
+	return out.String(), errors.Join(errs...)
+}
+
+// RenderFile renders a template file into its output path, creating parent directories
+func RenderFile(input, output string, data Data) error {
+	src, err := os.ReadFile(input)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	out, err := Render(string(src), data)
+	if err != nil {
+		return err
+	}
+	return writeOutput(output, out)
+}
+
+// Render renders the target's template into output, skipping a user template's front matter
+func (t Target) Render(output string, data Data) error {
+	if !t.User {
+		return RenderFile(t.Input, output, data)
+	}
+
+	src, err := os.ReadFile(t.Input)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+	out, err := RenderTemplate(string(src), data)
+	if err != nil {
+		return err
+	}
+	return writeOutput(output, out)
+}
+
+// writeOutput writes rendered output, creating parent directories
+func writeOutput(output, content string) error {
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return os.WriteFile(output, []byte(content), 0644)
+}
+
+// color is a color flowing through an expression's filters
+type color struct {
+	argb      uint32
+	format    string // Output format, e.g. "hex" or "rgb"
+	stripHash bool
+}
+
+// evaluate resolves a single expression: a value, optionally followed by "| filter args" steps
+func evaluate(expr string, data Data) (string, error) {
+	steps := strings.Split(expr, "|")
+	base := strings.TrimSpace(steps[0])
+
+	if base == "image" {
+		if len(steps) > 1 {
+			return "", fmt.Errorf("filters only apply to colors in {{%s}}", expr)
+		}
+		return data.Image, nil
+	}
+
+	parts := strings.Split(base, ".")
+	if len(parts) != 4 || parts[0] != "colors" {
+		return "", fmt.Errorf("unknown expression: {{%s}}", expr)
+	}
+	variant := parts[2]
+
+	argb, err := lookupColor(parts[1], variant, data)
+	if err != nil {
+		return "", fmt.Errorf("%v in {{%s}}", err, expr)
+	}
+	c := color{argb: argb, format: parts[3]}
+	if _, ok := formatColor(0, c.format); !ok {
+		return "", fmt.Errorf("unknown format %q in {{%s}}", c.format, expr)
+	}
+
+	for _, step := range steps[1:] {
+		if err := c.apply(strings.Fields(step), variant, data); err != nil {
+			return "", fmt.Errorf("%v in {{%s}}", err, expr)
+		}
+	}
+
+	value, _ := formatColor(c.argb, c.format)
+	if c.stripHash {
+		value = strings.TrimPrefix(value, "#")
+	}
+	return value, nil
+}
+
+// lookupColor returns a role's color in the dark, light or default scheme
+func lookupColor(role, variant string, data Data) (uint32, error) {
+	var colors map[string]uint32
+	switch variant {
+	case "default":
+		colors = data.Default()
+	case "dark":
+		colors = data.Dark
+	case "light":
+		colors = data.Light
+	default:
+		return 0, fmt.Errorf("unknown variant %q", variant)
+	}
+
+	argb, ok := colors[role]
+	if !ok {
+		return 0, fmt.Errorf("unknown color role %q", role)
+	}
+	return argb, nil
+}
+
+// Filters that change the output format rather than the color
+var formatFilters = map[string]bool{
+	"hex": true, "hex_stripped": true, "rgb": true, "rgba": true, "hsl": true, "hsla": true,
+}
+
+// apply runs one filter on the color. variant is the scheme the expression reads from,
+// which "mix" uses for bare role names.
+func (c *color) apply(fields []string, variant string, data Data) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("empty filter")
+	}
+	name, args := fields[0], fields[1:]
+
+	arity := map[string]int{"alpha": 1, "lighten": 1, "darken": 1, "mix": 2}
+	if formatFilters[name] || name == "strip_hash" {
+		arity[name] = 0
+	}
+	want, ok := arity[name]
+	if !ok {
+		return fmt.Errorf("unknown filter %q", name)
+	}
+	if len(args) != want {
+		return fmt.Errorf("filter %s takes %d argument(s), got %d", name, want, len(args))
+	}
+
+	switch name {
+	case "strip_hash":
+		c.stripHash = true
+	case "alpha":
+		alpha, err := parseAmount(args[0], 0, 1)
+		if err != nil {
+			return fmt.Errorf("alpha: %w", err)
+		}
+		c.argb = c.argb&0x00ffffff | uint32(math.Round(alpha*255))<<24
+	case "lighten", "darken":
+		amount, err := parseAmount(args[0], 0, 100)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if name == "darken" {
+			amount = -amount
+		}
+		hct := material.HctFromArgb(c.argb)
+		tone := math.Max(0, math.Min(100, hct.Tone()+amount))
+		c.argb = withAlpha(material.NewHct(hct.Hue(), hct.Chroma(), tone).Argb(), c.argb)
+	case "mix":
+		other, err := mixColor(args[0], variant, data)
+		if err != nil {
+			return fmt.Errorf("mix: %w", err)
+		}
+		amount, err := parseAmount(args[1], 0, 1)
+		if err != nil {
+			return fmt.Errorf("mix: %w", err)
+		}
+		c.argb = withAlpha(material.Mix(c.argb, other, amount), c.argb)
+	default:
+		c.format = name
+	}
+	return nil
+}
+
+// mixColor resolves the other color of a mix: a role name, colors.<role>.<variant> or a hex color
+func mixColor(ref, variant string, data Data) (uint32, error) {
+	if strings.HasPrefix(ref, "#") {
+		return material.ArgbFromHex(ref)
+	}
+	parts := strings.Split(ref, ".")
+	switch {
+	case len(parts) == 1:
+		return lookupColor(parts[0], variant, data)
+	case len(parts) == 3 && parts[0] == "colors":
+		return lookupColor(parts[1], parts[2], data)
+	}
+	return 0, fmt.Errorf("expected a role, colors.<role>.<variant> or #hex, got %q", ref)
+}
+
+// parseAmount parses a filter's numeric argument and checks its range
+func parseAmount(arg string, min, max float64) (float64, error) {
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number, got %q", arg)
+	}
+	if value < min || value > max {
+		return 0, fmt.Errorf("%s is outside %g-%g", arg, min, max)
+	}
+	return value, nil
+}
+
+// withAlpha gives a color the alpha of another
+func withAlpha(argb, from uint32) uint32 {
+	return argb&0x00ffffff | from&0xff000000
+}
+
+// formatColor renders a color in one of matugen's output formats
+func formatColor(argb uint32, format string) (string, bool) {
+	r := material.RedFromArgb(argb)
+	g := material.GreenFromArgb(argb)
+	b := material.BlueFromArgb(argb)
+	a := material.AlphaFromArgb(argb)
+
+	switch format {
+	case "hex":
+		return hexWithAlpha(argb), true
+	case "hex_stripped":
+		return strings.TrimPrefix(hexWithAlpha(argb), "#"), true
+	case "rgb":
+		return fmt.Sprintf("rgb(%d, %d, %d)", r, g, b), true
+	case "rgba":
+		return fmt.Sprintf("rgba(%d, %d, %d, %s)", r, g, b, formatAlpha(a)), true
+	case "hsl":
+		h, s, l := hslFromRgb(r, g, b)
+		return fmt.Sprintf("hsl(%d, %d%%, %d%%)", h, s, l), true
+	case "hsla":
+		h, s, l := hslFromRgb(r, g, b)
+		return fmt.Sprintf("hsla(%d, %d%%, %d%%, %s)", h, s, l, formatAlpha(a)), true
+	case "red":
+		return fmt.Sprint(r), true
+	case "green":
+		return fmt.Sprint(g), true
+	case "blue":
+		return fmt.Sprint(b), true
+	case "alpha":
+		return formatAlpha(a), true
+	}
+	return "", false
+}
+
+// hexWithAlpha is #rrggbb, or #rrggbbaa once a filter made the color translucent
+func hexWithAlpha(argb uint32) string {
+	if a := material.AlphaFromArgb(argb); a != 255 {
+		return fmt.Sprintf("%s%02x", material.HexFromArgb(argb), a)
+	}
+	return material.HexFromArgb(argb)
+}
+
+func formatAlpha(a int) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", float64(a)/255.0), "0"), ".")
+}
+
+// hslFromRgb converts 8-bit RGB to rounded HSL (degrees, percent, percent)
+func hslFromRgb(r, g, b int) (int, int, int) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	maxC := math.Max(rf, math.Max(gf, bf))
+	minC := math.Min(rf, math.Min(gf, bf))
+	l := (maxC + minC) / 2
+
+	var h, s float64
+	if d := maxC - minC; d != 0 {
+		if l > 0.5 {
+			s = d / (2 - maxC - minC)
+		} else {
+			s = d / (maxC + minC)
+		}
+		switch maxC {
+		case rf:
+			h = math.Mod((gf-bf)/d, 6)
+		case gf:
+			h = (bf-rf)/d + 2
+		default:
+			h = (rf-gf)/d + 4
+		}
+		h *= 60
+		if h < 0 {
+			h += 360
+		}
+	}
+
+	return int(math.Round(h)), int(math.Round(s * 100)), int(math.Round(l * 100))
+}
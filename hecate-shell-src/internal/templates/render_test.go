@@ -0,0 +1,229 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+// testData has a red dark scheme and a blue light scheme, with the dark one as default
+var testData = Data{
+	Image: "/home/user/wall.png",
+	Dark: map[string]uint32{
+		"primary":    0xffff0000,
+		"surface":    0xff000000,
+		"on_surface": 0xffffffff,
+	},
+	Light: map[string]uint32{
+		"primary":    0xff0000ff,
+		"surface":    0xffffffff,
+		"on_surface": 0xff000000,
+	},
+	IsDark: true,
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"image", "/home/user/wall.png"},
+		{"colors.primary.default.hex", "#ff0000"},
+		{"colors.primary.dark.hex", "#ff0000"},
+		{"colors.primary.light.hex", "#0000ff"},
+		{"colors.primary.default.hex_stripped", "ff0000"},
+		{"colors.primary.default.rgb", "rgb(255, 0, 0)"},
+		{"colors.primary.default.rgba", "rgba(255, 0, 0, 1)"},
+		{"colors.primary.default.hsl", "hsl(0, 100%, 50%)"},
+		{"colors.primary.light.hsla", "hsla(240, 100%, 50%, 1)"},
+		{"colors.primary.default.red", "255"},
+		{"colors.primary.default.green", "0"},
+		{"colors.primary.light.blue", "255"},
+		{"colors.primary.default.alpha", "1"},
+
+		// alpha
+		{"colors.primary.default.hex | alpha 0.5", "#ff000080"},
+		{"colors.primary.default.rgba | alpha 0.5", "rgba(255, 0, 0, 0.5)"},
+		{"colors.primary.default.hsla | alpha 0.25", "hsla(0, 100%, 50%, 0.25)"},
+		{"colors.primary.default.hex | alpha 1", "#ff0000"},
+		{"colors.primary.default.alpha | alpha 0", "0"},
+
+		// lighten and darken change the HCT tone and clamp it
+		{"colors.surface.default.hex | lighten 50", "#777777"},
+		{"colors.surface.default.hex | lighten 100", "#ffffff"},
+		{"colors.surface.default.hex | darken 20", "#000000"},
+		{"colors.on_surface.default.hex | darken 100", "#000000"},
+		{"colors.on_surface.default.hex | lighten 10", "#ffffff"},
+		{"colors.surface.default.hex | alpha 0.5 | lighten 100", "#ffffff80"},
+
+		// mix takes a role, colors.<role>.<variant> or a hex color
+		{"colors.surface.default.hex | mix on_surface 0.5", "#808080"},
+		{"colors.surface.light.hex | mix on_surface 0.5", "#808080"},
+		{"colors.surface.default.hex | mix #ffffff 0.25", "#404040"},
+		{"colors.surface.default.hex | mix colors.primary.light 1", "#0000ff"},
+		{"colors.surface.default.hex | mix colors.primary.light 0", "#000000"},
+		{"colors.surface.default.hex | alpha 0.5 | mix #ffffff 1", "#ffffff80"},
+
+		// format filters and strip_hash
+		{"colors.primary.default.hex | rgb", "rgb(255, 0, 0)"},
+		{"colors.primary.default.rgb | hex", "#ff0000"},
+		{"colors.primary.default.hex | hsl", "hsl(0, 100%, 50%)"},
+		{"colors.primary.default.hex | strip_hash", "ff0000"},
+		{"colors.primary.default.hex | alpha 0.5 | strip_hash", "ff000080"},
+		{"colors.primary.default.rgb | strip_hash", "rgb(255, 0, 0)"},
+		{" colors.primary.default.hex|lighten 0|strip_hash ", "ff0000"},
+	}
+
+	for _, tt := range tests {
+		got, err := evaluate(tt.expr, testData)
+		if err != nil {
+			t.Errorf("evaluate(%q): %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("evaluate(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"primary", "unknown expression: {{primary}}"},
+		{"colors.primary.hex", "unknown expression: {{colors.primary.hex}}"},
+		{"image | alpha 0.5", "filters only apply to colors in {{image | alpha 0.5}}"},
+		{"colors.nope.default.hex", `unknown color role "nope" in {{colors.nope.default.hex}}`},
+		{"colors.primary.dim.hex", `unknown variant "dim" in {{colors.primary.dim.hex}}`},
+		{"colors.primary.default.cmyk", `unknown format "cmyk" in {{colors.primary.default.cmyk}}`},
+		{"colors.primary.default.hex |", "empty filter in {{colors.primary.default.hex |}}"},
+		{"colors.primary.default.hex | blur 2", `unknown filter "blur" in {{colors.primary.default.hex | blur 2}}`},
+		{"colors.primary.default.hex | alpha", "filter alpha takes 1 argument(s), got 0 in {{colors.primary.default.hex | alpha}}"},
+		{"colors.primary.default.hex | mix on_surface", "filter mix takes 2 argument(s), got 1 in {{colors.primary.default.hex | mix on_surface}}"},
+		{"colors.primary.default.hex | strip_hash 1", "filter strip_hash takes 0 argument(s), got 1 in {{colors.primary.default.hex | strip_hash 1}}"},
+		{"colors.primary.default.hex | alpha 1.5", "alpha: 1.5 is outside 0-1 in {{colors.primary.default.hex | alpha 1.5}}"},
+		{"colors.primary.default.hex | alpha -0.1", "alpha: -0.1 is outside 0-1 in {{colors.primary.default.hex | alpha -0.1}}"},
+		{"colors.primary.default.hex | alpha half", `alpha: expected a number, got "half" in {{colors.primary.default.hex | alpha half}}`},
+		{"colors.primary.default.hex | lighten 200", "lighten: 200 is outside 0-100 in {{colors.primary.default.hex | lighten 200}}"},
+		{"colors.primary.default.hex | darken -5", "darken: -5 is outside 0-100 in {{colors.primary.default.hex | darken -5}}"},
+		{"colors.primary.default.hex | mix on_surface 2", "mix: 2 is outside 0-1 in {{colors.primary.default.hex | mix on_surface 2}}"},
+		{"colors.primary.default.hex | mix nope 0.5", `mix: unknown color role "nope" in {{colors.primary.default.hex | mix nope 0.5}}`},
+		{"colors.primary.default.hex | mix colors.primary 0.5", `mix: expected a role, colors.<role>.<variant> or #hex, got "colors.primary" in {{colors.primary.default.hex | mix colors.primary 0.5}}`},
+	}
+
+	for _, tt := range tests {
+		_, err := evaluate(tt.expr, testData)
+		if err == nil {
+			t.Errorf("evaluate(%q) succeeded, want %q", tt.expr, tt.want)
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("evaluate(%q) error = %q, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		arg      string
+		min, max float64
+		want     float64
+		wantErr  string
+	}{
+		{arg: "0", min: 0, max: 1, want: 0},
+		{arg: "1", min: 0, max: 1, want: 1},
+		{arg: ".5", min: 0, max: 1, want: 0.5},
+		{arg: "100", min: 0, max: 100, want: 100},
+		{arg: "1.01", min: 0, max: 1, wantErr: "1.01 is outside 0-1"},
+		{arg: "-1", min: 0, max: 100, wantErr: "-1 is outside 0-100"},
+		{arg: "10%", min: 0, max: 100, wantErr: `expected a number, got "10%"`},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAmount(tt.arg, tt.min, tt.max)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("parseAmount(%q) error = %v, want %q", tt.arg, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseAmount(%q) = %v, %v, want %v", tt.arg, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatColor(t *testing.T) {
+	tests := []struct {
+		argb   uint32
+		format string
+		want   string
+	}{
+		{0xff7aa2f7, "hex", "#7aa2f7"},
+		{0x807aa2f7, "hex", "#7aa2f780"},
+		{0x807aa2f7, "hex_stripped", "7aa2f780"},
+		{0xff7aa2f7, "rgb", "rgb(122, 162, 247)"},
+		{0x807aa2f7, "rgb", "rgb(122, 162, 247)"},
+		{0x807aa2f7, "rgba", "rgba(122, 162, 247, 0.5)"},
+		{0xff7aa2f7, "hsl", "hsl(221, 89%, 72%)"},
+		{0x407aa2f7, "hsla", "hsla(221, 89%, 72%, 0.25)"},
+		{0xff808080, "hsl", "hsl(0, 0%, 50%)"},
+		{0xff00ff00, "hsl", "hsl(120, 100%, 50%)"},
+		{0xffff00ff, "hsl", "hsl(300, 100%, 50%)"},
+		{0xff7aa2f7, "red", "122"},
+		{0xff7aa2f7, "green", "162"},
+		{0xff7aa2f7, "blue", "247"},
+		{0x007aa2f7, "alpha", "0"},
+	}
+
+	for _, tt := range tests {
+		got, ok := formatColor(tt.argb, tt.format)
+		if !ok || got != tt.want {
+			t.Errorf("formatColor(%#x, %s) = %q, %v, want %q", tt.argb, tt.format, got, ok, tt.want)
+		}
+	}
+	if _, ok := formatColor(0xff000000, "cmyk"); ok {
+		t.Error("formatColor accepted an unknown format")
+	}
+}
+
+// Failed expressions are left as written, and every error names its line
+func TestRenderErrors(t *testing.T) {
+	src := "bg={{colors.surface.default.hex}}\n" +
+		"fg={{colors.nope.default.hex}}\n" +
+		"accent={{ colors.primary.default.hex | lighten 200 }}\n" +
+		"open={{colors.primary.default.hex\n"
+	wantOut := "bg=#000000\n" +
+		"fg={{colors.nope.default.hex}}\n" +
+		"accent={{ colors.primary.default.hex | lighten 200 }}\n" +
+		"open={{colors.primary.default.hex\n"
+	wantErrs := []string{
+		`line 2: unknown color role "nope" in {{colors.nope.default.hex}}`,
+		"line 3: lighten: 200 is outside 0-100 in {{colors.primary.default.hex | lighten 200}}",
+		"line 4: unclosed {{",
+	}
+
+	out, err := Render(src, testData)
+	if out != wantOut {
+		t.Errorf("Render output = %q, want %q", out, wantOut)
+	}
+	if err == nil || err.Error() != strings.Join(wantErrs, "\n") {
+		t.Errorf("Render error = %v, want\n%s", err, strings.Join(wantErrs, "\n"))
+	}
+}
+
+// Line numbers count the front matter RenderTemplate skips
+func TestRenderTemplateLineNumbers(t *testing.T) {
+	src := "---\nname: foot\noutput: .config/foot/colors.ini\n---\n" +
+		"[colors]\nbackground={{colors.surface.default.hex_stripped}}\nforeground={{colors.fg.default.hex}}\n"
+
+	out, err := RenderTemplate(src, testData)
+	wantOut := "[colors]\nbackground=000000\nforeground={{colors.fg.default.hex}}\n"
+	if out != wantOut {
+		t.Errorf("RenderTemplate output = %q, want %q", out, wantOut)
+	}
+	wantErr := `line 7: unknown color role "fg" in {{colors.fg.default.hex}}`
+	if err == nil || err.Error() != wantErr {
+		t.Errorf("RenderTemplate error = %v, want %q", err, wantErr)
+	}
+}
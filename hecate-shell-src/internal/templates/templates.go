@@ -0,0 +1,501 @@
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"hecate-shell/internal/config"
+)
+
+// Some templates adapted from DankMaterialShell (https://github.com/AvengeMedia/DankMaterialShell)
+
+// postHookTimeout is how long a target's post hook may run
+const postHookTimeout = 10 * time.Second
+
+// Target is a template that gets rendered on every theme generation
+type Target struct {
+	Name        string // Template name, as used in matugen's [templates.<name>] tables
+	Description string // Short human-readable label
+	Input       string // Template file
+	Output      string // Generated file
+	Enabled     bool   // Whether the target is rendered
+	PostHook    string // Shell command run after the target is rendered
+	User        bool   // Input is from the user templates dir and may start with front matter
+	Include     *Include
+	Block       *Block
+}
+
+// Include is a line that makes an application load a generated file, kept in the
+// application's main config
+type Include struct {
+	Config   string // Main config file
+	Line     string // Line to add, e.g. "include=~/.config/foot/hecate-colors.ini"
+	Before   string // Regexp of the line to insert before; empty to add at the top
+	Replace  string // Regexp of an existing setting the line replaces, if any
+	Optional bool   // Leave the config alone if it doesn't exist instead of creating it
+}
+
+// Block keeps a copy of the rendered output inside a config that can't include other
+// files, such as lazygit's config.yml or starship.toml
+type Block struct {
+	Config string // Config file
+	Parent string // Top-level key the output is nested under (its first line), if any
+}
+
+// manifestFile is the layout of config/templates.json and templates.d/*.json
+type manifestFile struct {
+	Templates []manifestEntry `json:"templates"`
+}
+
+// manifestEntry is one template declaration. Input is relative to the manifest's
+// template directory, output is relative to $HOME unless it starts with "@shell/"
+// (the HecateShell config dir). Both may also be absolute or start with "~/".
+type manifestEntry struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Input       string           `json:"input"`
+	Output      string           `json:"output"`
+	Enabled     *bool            `json:"enabled,omitempty"`     // Defaults to true
+	WhenExists  pathList         `json:"when_exists,omitempty"` // Only enabled by default if one of these paths exists
+	PostHook    string           `json:"post_hook,omitempty"`
+	Include     *manifestInclude `json:"include,omitempty"`
+	Block       *manifestBlock   `json:"block,omitempty"`
+}
+
+// manifestInclude declares an Include; config is resolved like an output path, and when
+// several are listed the first that exists is used. before may use capture groups in line
+// as $1, $2, ...
+type manifestInclude struct {
+	Config   pathList `json:"config"`
+	Line     string   `json:"line"`
+	Before   string   `json:"before,omitempty"`
+	Replace  string   `json:"replace,omitempty"`
+	Optional bool     `json:"optional,omitempty"`
+}
+
+// pathList is a manifest path that may also be given as a list of alternatives
+type pathList []string
+
+// UnmarshalJSON accepts a single path or a list of paths
+func (p *pathList) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*p = pathList{path}
+		return nil
+	}
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return fmt.Errorf("expected a path or a list of paths")
+	}
+	*p = paths
+	return nil
+}
+
+// manifestBlock declares a Block; config is resolved like an output path
+type manifestBlock struct {
+	Config string `json:"config"`
+	Parent string `json:"parent,omitempty"`
+}
+
+// GetManifestFile returns the path to the shipped templates manifest
+func GetManifestFile() (string, error) {
+	shellDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(shellDir, "config", "templates.json"), nil
+}
+
+// GetUserManifestDir returns the directory users drop extra manifests into
+func GetUserManifestDir() (string, error) {
+	shellDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(shellDir, "templates.d"), nil
+}
+
+// GetUserTemplateDir returns the directory of user templates, which shadow shipped
+// templates of the same name or declare new targets with front matter
+func GetUserTemplateDir() (string, error) {
+	shellDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(shellDir, "templates"), nil
+}
+
+// Manifest returns every declared template target, enabled or not, with absolute paths.
+// Entries in templates.d/*.json replace shipped entries of the same name, files in the
+// user templates dir replace shipped templates, and the theme.targets section of
+// config.json has the final say on what is enabled.
+func Manifest() ([]Target, error) {
+	shellDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	manifestPath, err := GetManifestFile()
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []Target
+	index := make(map[string]int)
+	put := func(target Target) {
+		if i, ok := index[target.Name]; ok {
+			targets[i] = target
+			return
+		}
+		index[target.Name] = len(targets)
+		targets = append(targets, target)
+	}
+	add := func(path, inputDir string) error {
+		entries, err := readManifest(path)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			target := Target{
+				Name:        entry.Name,
+				Description: entry.Description,
+				Input:       resolvePath(entry.Input, inputDir, homeDir),
+				Output:      resolveOutput(entry.Output, shellDir, homeDir),
+				Enabled:     entry.Enabled == nil || *entry.Enabled,
+				PostHook:    entry.PostHook,
+			}
+			if len(entry.WhenExists) > 0 && target.Enabled {
+				_, target.Enabled = existingPath(entry.WhenExists, shellDir, homeDir)
+			}
+			if entry.Include != nil {
+				config, _ := existingPath(entry.Include.Config, shellDir, homeDir)
+				target.Include = &Include{
+					Config:   config,
+					Line:     entry.Include.Line,
+					Before:   entry.Include.Before,
+					Replace:  entry.Include.Replace,
+					Optional: entry.Include.Optional,
+				}
+			}
+			if entry.Block != nil {
+				target.Block = &Block{
+					Config: resolveOutput(entry.Block.Config, shellDir, homeDir),
+					Parent: entry.Block.Parent,
+				}
+			}
+			put(target)
+		}
+		return nil
+	}
+
+	shippedDir := filepath.Join(shellDir, "config", "templates")
+	if err := add(manifestPath, shippedDir); err != nil {
+		return nil, err
+	}
+
+	userDir, err := GetUserManifestDir()
+	if err != nil {
+		return nil, err
+	}
+	userManifests, _ := filepath.Glob(filepath.Join(userDir, "*.json"))
+	sort.Strings(userManifests)
+	for _, path := range userManifests {
+		if err := add(path, userDir); err != nil {
+			return nil, err
+		}
+	}
+
+	userTemplates, err := readUserTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, tmpl := range userTemplates {
+		shadowed := false
+		for i := range targets {
+			if targets[i].Input == filepath.Join(shippedDir, tmpl.rel) {
+				targets[i] = tmpl.apply(targets[i], shellDir, homeDir)
+				shadowed = true
+			}
+		}
+		if !shadowed && tmpl.entry.Output != "" {
+			target := Target{Name: tmpl.entry.Name, Enabled: true}
+			if target.Name == "" {
+				target.Name = strings.TrimSuffix(filepath.Base(tmpl.path), filepath.Ext(tmpl.path))
+			}
+			put(tmpl.apply(target, shellDir, homeDir))
+		}
+	}
+
+	settings, err := config.LoadThemeSettings()
+	if err != nil {
+		return nil, err
+	}
+	for i := range targets {
+		if enabled, ok := settings.Targets[targets[i].Name]; ok {
+			targets[i].Enabled = enabled
+		}
+	}
+
+	return targets, nil
+}
+
+// Targets returns the enabled template targets with absolute paths
+func Targets() ([]Target, error) {
+	manifest, err := Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]Target, 0, len(manifest))
+	for _, target := range manifest {
+		if target.Enabled {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// SetEnabled enables or disables a target, saving the choice to config.json
+func SetEnabled(name string, enabled bool) error {
+	manifest, err := Manifest()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, target := range manifest {
+		if target.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown template target: %s", name)
+	}
+
+	settings, err := config.LoadThemeSettings()
+	if err != nil {
+		return err
+	}
+	if settings.Targets == nil {
+		settings.Targets = make(map[string]bool)
+	}
+	settings.Targets[name] = enabled
+	return config.SaveThemeSettings(settings)
+}
+
+// userTemplate is a file in the user templates dir
+type userTemplate struct {
+	path  string        // Absolute path
+	rel   string        // Path relative to the user templates dir
+	entry manifestEntry // Front matter, if any
+}
+
+// readUserTemplates lists the user templates dir and parses each file's front matter
+func readUserTemplates() ([]userTemplate, error) {
+	dir, err := GetUserTemplateDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []userTemplate
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read user template: %w", err)
+		}
+		entry, _, _, err := parseFrontMatter(string(src))
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		found = append(found, userTemplate{path: path, rel: rel, entry: entry})
+		return nil
+	})
+	return found, err
+}
+
+// apply points a target at the user template, with any fields its front matter sets
+func (t userTemplate) apply(target Target, shellDir, homeDir string) Target {
+	target.Input = t.path
+	target.User = true
+	if t.entry.Description != "" {
+		target.Description = t.entry.Description
+	}
+	if t.entry.Output != "" {
+		target.Output = resolveOutput(t.entry.Output, shellDir, homeDir)
+	}
+	if t.entry.Enabled != nil {
+		target.Enabled = *t.entry.Enabled
+	}
+	if t.entry.PostHook != "" {
+		target.PostHook = t.entry.PostHook
+	}
+	return target
+}
+
+// readManifest parses a single manifest file
+func readManifest(path string) ([]manifestEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates manifest: %w", err)
+	}
+
+	var manifest manifestFile
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	for _, entry := range manifest.Templates {
+		if entry.Name == "" || entry.Input == "" || entry.Output == "" {
+			return nil, fmt.Errorf("invalid entry in %s: name, input and output are required", path)
+		}
+		if entry.Include != nil {
+			if len(entry.Include.Config) == 0 || slices.Contains(entry.Include.Config, "") || entry.Include.Line == "" {
+				return nil, fmt.Errorf("invalid include for %s in %s: config and line are required", entry.Name, path)
+			}
+			for _, expr := range []string{entry.Include.Before, entry.Include.Replace} {
+				if _, err := regexp.Compile(expr); err != nil {
+					return nil, fmt.Errorf("invalid include for %s in %s: %w", entry.Name, path, err)
+				}
+			}
+		}
+		if entry.Block != nil && entry.Block.Config == "" {
+			return nil, fmt.Errorf("invalid block for %s in %s: config is required", entry.Name, path)
+		}
+	}
+	return manifest.Templates, nil
+}
+
+// existingPath resolves the first of the paths that exists, like an output path. If none
+// exists, the first path is returned with ok set to false.
+func existingPath(paths pathList, shellDir, homeDir string) (path string, ok bool) {
+	for _, p := range paths {
+		resolved := resolveOutput(p, shellDir, homeDir)
+		if _, err := os.Stat(resolved); err == nil {
+			return resolved, true
+		}
+	}
+	if len(paths) == 0 {
+		return "", false
+	}
+	return resolveOutput(paths[0], shellDir, homeDir), false
+}
+
+// resolvePath expands a path relative to baseDir, honouring absolute and "~/" paths
+func resolvePath(path, baseDir, homeDir string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return filepath.Join(homeDir, rest)
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+// resolveOutput expands an output path relative to $HOME or the shell dir
+func resolveOutput(output, shellDir, homeDir string) string {
+	if rest, ok := strings.CutPrefix(output, "@shell/"); ok {
+		return filepath.Join(shellDir, rest)
+	}
+	return resolvePath(output, homeDir, homeDir)
+}
+
+// Rendered returns the enabled targets whose output exists, i.e. those a theme change
+// has rendered, whether in this run or an earlier one
+func Rendered() ([]Target, error) {
+	targets, err := Targets()
+	if err != nil {
+		return nil, err
+	}
+
+	var rendered []Target
+	for _, target := range targets {
+		if _, err := os.Stat(target.Output); err == nil {
+			rendered = append(rendered, target)
+		}
+	}
+	return rendered, nil
+}
+
+// RenderAll renders every target, continuing past failures (like matugen's --continue-on-error).
+// The returned error joins the failures of individual targets.
+func RenderAll(targets []Target, data Data) error {
+	var errs []error
+	for _, target := range targets {
+		if err := target.Render(target.Output, data); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", target.Name, err))
+			continue
+		}
+		if target.Include != nil {
+			if err := target.Include.Ensure(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", target.Name, err))
+			}
+		}
+		if target.Block != nil {
+			output, err := os.ReadFile(target.Output)
+			if err == nil {
+				err = target.Block.Ensure(string(output))
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", target.Name, err))
+			}
+		}
+		if target.PostHook != "" {
+			if err := runPostHook(target.PostHook); err != nil {
+				errs = append(errs, fmt.Errorf("%s: post hook failed: %w", target.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runPostHook runs a target's post hook with the timeout
+func runPostHook(command string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postHookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	// Don't wait for children of a killed hook that still hold its output open
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", postHookTimeout)
+	}
+	return err
+}
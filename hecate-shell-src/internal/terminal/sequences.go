@@ -0,0 +1,151 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"hecate-shell/internal/material"
+)
+
+// shells are the process names treated as interactive shells waiting at a prompt
+var shells = map[string]bool{
+	"bash": true, "zsh": true, "fish": true, "sh": true, "dash": true, "ksh": true,
+	"mksh": true, "oksh": true, "yash": true, "tcsh": true, "csh": true,
+	"nu": true, "elvish": true, "xonsh": true, "ion": true,
+}
+
+// Sequences builds the OSC escape sequences that set a terminal's 16 ANSI colors (OSC 4),
+// foreground (OSC 10), background (OSC 11) and cursor (OSC 12), the same colors as the
+// kitty template
+func Sequences(colors map[string]uint32) (string, error) {
+	var b strings.Builder
+	osc := func(code, role string) error {
+		argb, ok := colors[role]
+		if !ok {
+			return fmt.Errorf("missing color role %s", role)
+		}
+		fmt.Fprintf(&b, "\x1b]%s;%s\x1b\\", code, material.HexFromArgb(argb))
+		return nil
+	}
+
+	for i, role := range material.TerminalRoles {
+		if err := osc("4;"+strconv.Itoa(i), role); err != nil {
+			return "", err
+		}
+	}
+	for _, seq := range [][2]string{{"10", "on_surface"}, {"11", "background"}, {"12", "primary_container"}} {
+		if err := osc(seq[0], seq[1]); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// GetCacheFile returns the path of the sequences file shells can cat on startup
+func GetCacheFile() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "hecate", "sequences"), nil
+}
+
+// WriteCache saves the sequences for new shells
+func WriteCache(seq string) error {
+	path, err := GetCacheFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(seq), 0644); err != nil {
+		return fmt.Errorf("failed to write sequences: %w", err)
+	}
+	return nil
+}
+
+// Broadcast writes the sequences to every pseudo-terminal of the current user whose
+// foreground process is a shell, so full-screen programs are left alone. It returns
+// how many terminals were recolored.
+func Broadcast(seq string) (int, error) {
+	ptys, err := filepath.Glob("/dev/pts/[0-9]*")
+	if err != nil {
+		return 0, err
+	}
+	foreground := foregroundProcesses()
+
+	uid := uint32(os.Getuid())
+	recolored := 0
+	for _, pty := range ptys {
+		var st syscall.Stat_t
+		if err := syscall.Stat(pty, &st); err != nil || st.Uid != uid {
+			continue
+		}
+		pid, ok := foreground[ttyNumber(uint64(st.Rdev))]
+		if !ok || !shells[processName(pid)] {
+			continue
+		}
+
+		// Non-blocking, so a stuck terminal can't hang theme generation
+		f, err := os.OpenFile(pty, os.O_WRONLY|syscall.O_NOCTTY|syscall.O_NONBLOCK, 0)
+		if err != nil {
+			continue
+		}
+		if _, err := f.WriteString(seq); err == nil {
+			recolored++
+		}
+		f.Close()
+	}
+	return recolored, nil
+}
+
+// foregroundProcesses maps each controlling terminal (as a /proc tty_nr) to its
+// foreground process group, read from /proc/<pid>/stat
+func foregroundProcesses() map[uint64]int {
+	foreground := make(map[uint64]int)
+	stats, _ := filepath.Glob("/proc/[0-9]*/stat")
+	for _, path := range stats {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		// The command name can contain spaces and parentheses, so split after the last ')'
+		i := strings.LastIndexByte(string(data), ')')
+		if i < 0 {
+			continue
+		}
+		// state ppid pgrp session tty_nr tpgid ...
+		fields := strings.Fields(string(data[i+1:]))
+		if len(fields) < 6 {
+			continue
+		}
+		tty, err1 := strconv.ParseUint(fields[4], 10, 64)
+		tpgid, err2 := strconv.Atoi(fields[5])
+		if err1 != nil || err2 != nil || tty == 0 || tpgid <= 0 {
+			continue
+		}
+		foreground[tty] = tpgid
+	}
+	return foreground
+}
+
+// processName returns the command name of a process
+func processName(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// ttyNumber converts a device number from stat into the kernel encoding /proc uses for tty_nr
+func ttyNumber(dev uint64) uint64 {
+	major := (dev>>8)&0xfff | (dev>>32)&^uint64(0xfff)
+	minor := dev&0xff | (dev>>12)&^uint64(0xff)
+	return minor&0xff | major<<8 | (minor&^uint64(0xff))<<12
+}
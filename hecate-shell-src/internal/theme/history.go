@@ -0,0 +1,173 @@
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+
+	"hecate-shell/internal/config"
+)
+
+// historyLimit bounds the number of themes kept for undo/redo
+const historyLimit = 20
+
+// history is the layout of history.json. Position is the index of the live theme.
+type history struct {
+	Position int        `json:"position"`
+	Entries  []Snapshot `json:"entries"`
+}
+
+// RecordHistory appends the live theme to the history, dropping any redo states.
+// Recording the theme that is already current is a no-op.
+func RecordHistory() error {
+	snapshot, err := currentSnapshot("")
+	if err != nil {
+		return err
+	}
+
+	h, err := loadHistory()
+	if err != nil {
+		return err
+	}
+
+	if len(h.Entries) > 0 && sameTheme(h.Entries[h.Position], snapshot) {
+		return nil
+	}
+
+	h.Entries = append(h.Entries[:min(h.Position+1, len(h.Entries))], snapshot)
+	if len(h.Entries) > historyLimit {
+		h.Entries = h.Entries[len(h.Entries)-historyLimit:]
+	}
+	h.Position = len(h.Entries) - 1
+
+	return h.save()
+}
+
+// Undo re-applies the theme before the current one
+func Undo() (Snapshot, error) {
+	return step(-1)
+}
+
+// Redo re-applies the theme undone last
+func Redo() (Snapshot, error) {
+	return step(1)
+}
+
+// History returns the recorded themes, oldest first, and the index of the live one
+func History() ([]Snapshot, int, error) {
+	h, err := loadHistory()
+	if err != nil {
+		return nil, 0, err
+	}
+	return h.Entries, h.Position, nil
+}
+
+// CanUndo returns an error if there is no theme before the current one
+func CanUndo() error {
+	h, err := loadHistory()
+	if err != nil {
+		return err
+	}
+	return h.check(-1)
+}
+
+// CanRedo returns an error if there is no undone theme to re-apply
+func CanRedo() error {
+	h, err := loadHistory()
+	if err != nil {
+		return err
+	}
+	return h.check(1)
+}
+
+// step moves through the history and applies the theme it lands on
+func step(delta int) (Snapshot, error) {
+	h, err := loadHistory()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := h.check(delta); err != nil {
+		return Snapshot{}, err
+	}
+
+	target := h.Position + delta
+
+	snapshot := h.Entries[target]
+	if err := ApplySnapshot(snapshot); err != nil {
+		return Snapshot{}, err
+	}
+
+	h.Position = target
+	return snapshot, h.save()
+}
+
+// check returns an error if moving delta entries through the history leaves it
+func (h history) check(delta int) error {
+	if target := h.Position + delta; target < 0 || target >= len(h.Entries) {
+		if delta < 0 {
+			return fmt.Errorf("nothing to undo")
+		}
+		return fmt.Errorf("nothing to redo")
+	}
+	return nil
+}
+
+// sameTheme reports whether two snapshots describe the same live theme
+func sameTheme(a, b Snapshot) bool {
+	if a.Wallpaper != b.Wallpaper || !reflect.DeepEqual(a.Palette, b.Palette) {
+		return false
+	}
+
+	var themeA, themeB bytes.Buffer
+	if json.Compact(&themeA, a.Theme) != nil || json.Compact(&themeB, b.Theme) != nil {
+		return false
+	}
+	return bytes.Equal(themeA.Bytes(), themeB.Bytes())
+}
+
+// loadHistory reads history.json. A missing file is an empty history.
+func loadHistory() (history, error) {
+	var h history
+
+	path, err := config.GetHistoryFile()
+	if err != nil {
+		return h, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return h, nil
+		}
+		return h, fmt.Errorf("failed to read history.json: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &h); err != nil {
+		return h, fmt.Errorf("failed to parse history.json: %w", err)
+	}
+	if h.Position < 0 || h.Position >= len(h.Entries) {
+		h.Position = len(h.Entries) - 1
+	}
+	return h, nil
+}
+
+// save writes history.json
+func (h history) save() error {
+	path, err := config.GetHistoryFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(h, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history.json: %w", err)
+	}
+	return nil
+}
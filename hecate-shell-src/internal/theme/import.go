@@ -0,0 +1,137 @@
+package theme
+
+import (
+	"fmt"
+
+	"hecate-shell/internal/colorscheme"
+	"hecate-shell/internal/material"
+)
+
+// Surface container steps, as the fraction of the way from background to foreground
+var containerSteps = []struct {
+	role   string
+	amount float64
+}{
+	{"surface_container_low", 0.04},
+	{"surface_container", 0.08},
+	{"surface_container_high", 0.12},
+	{"surface_container_highest", 0.16},
+	{"surface_bright", 0.20},
+}
+
+// Import maps a community color scheme onto the Material roles and renders every
+// template target from it. The scheme's own mode gets its exact colors; the other
+// mode falls back to a Material scheme of the opts variant seeded from its accent color.
+// A dark or light opts mode renders that mode instead of the scheme's own.
+func Import(path string, opts Options) (Palette, error) {
+	scheme, err := colorscheme.Load(path)
+	if err != nil {
+		return Palette{}, err
+	}
+
+	p := paletteFromScheme(scheme, opts.scheme())
+	if opts.Mode == ModeDark || opts.Mode == ModeLight {
+		p.Mode = opts.Mode
+	}
+	palette, err := applyOverrides(p)
+	if err != nil {
+		return Palette{}, err
+	}
+	fmt.Printf("Imported %s scheme %q (%s)\n", scheme.Format, scheme.Name, palette.Mode)
+
+	if err := Apply(palette); err != nil {
+		return Palette{}, err
+	}
+	return palette, nil
+}
+
+// paletteFromScheme maps terminal colors onto Material roles
+func paletteFromScheme(scheme colorscheme.Scheme, variant material.Variant) Palette {
+	bg, fg := scheme.Background, scheme.Foreground
+	isDark := material.HctFromArgb(bg).Tone() < autoLightThreshold
+
+	// Blue is the accent in nearly every terminal scheme
+	accent := scheme.ANSI[4]
+	generated := material.NewScheme(accent, variant, isDark).Colors()
+	other := material.NewScheme(accent, variant, !isDark).Colors()
+
+	colors := make(map[string]uint32, len(generated))
+	for role, argb := range generated {
+		colors[role] = argb
+	}
+
+	accents := []struct {
+		role  string
+		color uint32
+	}{
+		{"primary", scheme.ANSI[4]},
+		{"secondary", scheme.ANSI[5]},
+		{"tertiary", scheme.ANSI[6]},
+		{"error", scheme.ANSI[1]},
+		{"success", scheme.ANSI[2]},
+		{"warning", scheme.ANSI[3]},
+		{"info", scheme.ANSI[4]},
+	}
+	for _, a := range accents {
+		container := material.Mix(a.color, bg, 0.7)
+		colors[a.role] = a.color
+		colors["on_"+a.role] = readableOn(a.color, bg, fg)
+		colors[a.role+"_container"] = container
+		colors["on_"+a.role+"_container"] = readableOn(container, bg, fg)
+	}
+	colors["inverse_primary"] = material.Mix(scheme.ANSI[4], fg, 0.5)
+	colors["surface_tint"] = scheme.ANSI[4]
+	colors["source_color"] = accent
+
+	colors["background"] = bg
+	colors["surface"] = bg
+	colors["surface_dim"] = bg
+	colors["on_background"] = fg
+	colors["on_surface"] = fg
+	colors["inverse_surface"] = fg
+	colors["inverse_on_surface"] = bg
+
+	edge := uint32(0xff000000)
+	if !isDark {
+		edge = 0xffffffff
+	}
+	colors["surface_container_lowest"] = material.Mix(bg, edge, 0.3)
+	for _, step := range containerSteps {
+		colors[step.role] = material.Mix(bg, fg, step.amount)
+	}
+	if len(scheme.Surfaces) >= 2 {
+		colors["surface_container"] = scheme.Surfaces[0]
+		colors["surface_container_high"] = scheme.Surfaces[1]
+	}
+
+	// The scheme's own terminal colors are kept as they are
+	for i, role := range material.TerminalRoles {
+		colors[role] = scheme.ANSI[i]
+	}
+
+	colors["surface_variant"] = colors["surface_container_highest"]
+	colors["on_surface_variant"] = material.Mix(fg, bg, 0.25)
+	colors["outline"] = material.Mix(fg, bg, 0.5)
+	colors["outline_variant"] = material.Mix(fg, bg, 0.75)
+
+	palette := Palette{
+		Source: material.HexFromArgb(accent),
+		Name:   scheme.Name,
+		Mode:   modeName(isDark),
+		Scheme: scheme.Format,
+	}
+	if isDark {
+		palette.Dark, palette.Light = hexColors(colors), hexColors(other)
+	} else {
+		palette.Dark, palette.Light = hexColors(other), hexColors(colors)
+	}
+	return palette
+}
+
+// readableOn picks whichever of the scheme's background and foreground reads better on a color
+func readableOn(color, bg, fg uint32) uint32 {
+	if material.ContrastRatio(color, bg) > material.ContrastRatio(color, fg) {
+		return bg
+	}
+	return fg
+}
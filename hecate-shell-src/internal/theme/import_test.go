@@ -0,0 +1,152 @@
+package theme
+
+import (
+	"path/filepath"
+	"testing"
+
+	"hecate-shell/internal/colorscheme"
+)
+
+// The palettes Import builds, short of applying them. The scheme's own mode keeps its
+// exact colors; the other mode is generated.
+func TestImportSchemes(t *testing.T) {
+	tests := []struct {
+		file   string
+		format string
+		name   string
+		mode   string
+		roles  map[string]string
+	}{
+		{
+			file:   "base16.yaml",
+			format: colorscheme.FormatBase16,
+			name:   "Gruvbox dark, medium",
+			mode:   ModeDark,
+			roles: map[string]string{
+				"primary":                "#83a598",
+				"secondary":              "#d3869b",
+				"tertiary":               "#8ec07c",
+				"error":                  "#fb4934",
+				"success":                "#b8bb26",
+				"warning":                "#fabd2f",
+				"info":                   "#83a598",
+				"background":             "#282828",
+				"surface":                "#282828",
+				"on_surface":             "#d5c4a1",
+				"surface_container":      "#3c3836",
+				"surface_container_high": "#504945",
+				"term0":                  "#282828",
+				"term8":                  "#665c54",
+				"term9":                  "#fb4934",
+				"term15":                 "#fbf1c7",
+			},
+		},
+		{
+			file:   "base24.yaml",
+			format: colorscheme.FormatBase24,
+			name:   "Paper Light",
+			mode:   ModeLight,
+			roles: map[string]string{
+				"primary":           "#4078f2",
+				"secondary":         "#a626a4",
+				"tertiary":          "#0184bc",
+				"error":             "#e45649",
+				"success":           "#50a14f",
+				"warning":           "#c18401",
+				"background":        "#fafafa",
+				"on_surface":        "#383a42",
+				"surface_container": "#f0f0f0",
+				"term1":             "#e45649",
+				"term9":             "#ff6b5e",
+				"term12":            "#5a8dff",
+				"term15":            "#090a0b",
+			},
+		},
+		{
+			file:   "colors.json",
+			format: colorscheme.FormatPywal,
+			name:   "colors",
+			mode:   ModeDark,
+			roles: map[string]string{
+				"primary":    "#4f7f8f",
+				"secondary":  "#7a6f9a",
+				"tertiary":   "#5f9a94",
+				"error":      "#5d8a6b",
+				"success":    "#6f9a5a",
+				"warning":    "#a3a160",
+				"background": "#0f1512",
+				"on_surface": "#d3dcd6",
+				"term0":      "#0f1512",
+				"term8":      "#939a95",
+			},
+		},
+		{
+			file:   "gogh.yml",
+			format: colorscheme.FormatGogh,
+			name:   "Tokyo Night",
+			mode:   ModeDark,
+			roles: map[string]string{
+				"primary":    "#7aa2f7",
+				"secondary":  "#bb9af7",
+				"tertiary":   "#7dcfff",
+				"error":      "#f7768e",
+				"success":    "#9ece6a",
+				"warning":    "#e0af68",
+				"background": "#1a1b26",
+				"on_surface": "#c0caf5",
+				"term0":      "#15161e",
+				"term7":      "#a9b1d6",
+				"term15":     "#c0caf5",
+			},
+		},
+		{
+			file:   "Basic Light.itermcolors",
+			format: colorscheme.FormatITerm2,
+			name:   "Basic Light",
+			mode:   ModeLight,
+			roles: map[string]string{
+				"primary":    "#0033cc",
+				"secondary":  "#990099",
+				"tertiary":   "#006699",
+				"error":      "#cc0000",
+				"success":    "#009900",
+				"warning":    "#996600",
+				"background": "#ffffff",
+				"on_surface": "#333333",
+				"term0":      "#000000",
+				"term12":     "#3366ff",
+				"term15":     "#ffffff",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		scheme, err := colorscheme.Load(filepath.Join("testdata", tt.file))
+		if err != nil {
+			t.Errorf("%s: %v", tt.file, err)
+			continue
+		}
+		p := paletteFromScheme(scheme, Options{}.scheme())
+
+		if p.Scheme != tt.format || p.Name != tt.name || p.Mode != tt.mode {
+			t.Errorf("%s: scheme, name, mode = %q, %q, %q, want %q, %q, %q",
+				tt.file, p.Scheme, p.Name, p.Mode, tt.format, tt.name, tt.mode)
+		}
+		if p.Source != tt.roles["primary"] {
+			t.Errorf("%s: source = %s, want %s", tt.file, p.Source, tt.roles["primary"])
+		}
+
+		own, other := p.Dark, p.Light
+		if tt.mode == ModeLight {
+			own, other = p.Light, p.Dark
+		}
+		for role, want := range tt.roles {
+			if got := own[role]; got != want {
+				t.Errorf("%s: %s = %s, want %s", tt.file, role, got, want)
+			}
+		}
+		if len(other) != len(own) {
+			t.Errorf("%s: other mode has %d roles, want %d", tt.file, len(other), len(own))
+		}
+	}
+}
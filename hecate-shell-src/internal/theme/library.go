@@ -0,0 +1,228 @@
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"hecate-shell/internal/config"
+)
+
+// Snapshot is a named theme saved under themes/<name>.json
+type Snapshot struct {
+	Name      string               `json:"name"`
+	Created   time.Time            `json:"created"`
+	Wallpaper string               `json:"wallpaper,omitempty"`
+	Settings  config.ThemeSettings `json:"settings"`
+	Palette   Palette              `json:"palette"`
+	Theme     json.RawMessage      `json:"theme"` // theme.json as it was when saved
+}
+
+// snapshotNameRe restricts names to something safe to use as a file name
+var snapshotNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SaveSnapshot saves the current theme under a name, replacing any snapshot with that name
+func SaveSnapshot(name string) (Snapshot, error) {
+	path, err := snapshotPath(name)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot, err := currentSnapshot(name)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "    ")
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to create themes directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// currentSnapshot captures the live theme
+func currentSnapshot(name string) (Snapshot, error) {
+	palette, err := LoadPalette()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	themeFile, err := config.GetThemeFile()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	themeData, err := os.ReadFile(themeFile)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read theme.json: %w", err)
+	}
+	if !json.Valid(themeData) {
+		return Snapshot{}, fmt.Errorf("theme.json is not valid JSON")
+	}
+
+	settings, err := config.LoadThemeSettings()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	// Target toggles are a user preference, not part of a theme
+	settings.Targets = nil
+
+	wallpaper, _ := config.GetWallpaperPath()
+
+	return Snapshot{
+		Name:      name,
+		Created:   time.Now(),
+		Wallpaper: wallpaper,
+		Settings:  settings,
+		Palette:   palette,
+		Theme:     json.RawMessage(themeData),
+	}, nil
+}
+
+// LoadSnapshot reads a saved theme
+func LoadSnapshot(name string) (Snapshot, error) {
+	path, err := snapshotPath(name)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Snapshot{}, fmt.Errorf("no saved theme named %q", name)
+		}
+		return Snapshot{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to parse snapshot %s: %w", name, err)
+	}
+	snapshot.Name = name
+	return snapshot, nil
+}
+
+// ListSnapshots returns every saved theme, sorted by name
+func ListSnapshots() ([]Snapshot, error) {
+	dir, err := config.GetThemesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	snapshots := make([]Snapshot, 0, len(paths))
+	for _, path := range paths {
+		snapshot, err := LoadSnapshot(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// RemoveSnapshot deletes a saved theme
+func RemoveSnapshot(name string) error {
+	path, err := snapshotPath(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no saved theme named %q", name)
+		}
+		return fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+	return nil
+}
+
+// WithOptions returns the snapshot with the mode and scheme set in opts replacing its
+// saved settings. Another mode renders the palette's other variant, so the saved
+// theme.json is left out.
+func (s Snapshot) WithOptions(opts Options) Snapshot {
+	if opts.Mode != "" {
+		s.Settings.Mode = opts.Mode
+		if opts.Mode != ModeAuto && opts.Mode != s.Palette.Mode {
+			s.Palette.Mode = opts.Mode
+			s.Theme = nil
+		}
+	}
+	if opts.Scheme != "" {
+		s.Settings.Scheme = string(opts.Scheme)
+	}
+	return s
+}
+
+// ApplySnapshot restores a saved theme: its wallpaper and scheme settings go back into
+// config.json and every template target is re-rendered from its palette
+func ApplySnapshot(snapshot Snapshot) error {
+	settings, err := config.LoadThemeSettings()
+	if err != nil {
+		return err
+	}
+	settings.Backend = snapshot.Settings.Backend
+	settings.Mode = snapshot.Settings.Mode
+	settings.Scheme = snapshot.Settings.Scheme
+	if err := config.SaveThemeSettings(settings); err != nil {
+		return err
+	}
+
+	if snapshot.Wallpaper != "" {
+		if err := config.SetWallpaperPath(snapshot.Wallpaper); err != nil {
+			return err
+		}
+	}
+
+	if err := Apply(snapshot.Palette); err != nil {
+		return err
+	}
+
+	// Restore theme.json exactly as saved, including any hand edits
+	if snapshot.Theme == nil {
+		return nil
+	}
+	themeFile, err := config.GetThemeFile()
+	if err != nil {
+		return err
+	}
+	var themeData bytes.Buffer
+	if err := json.Indent(&themeData, snapshot.Theme, "", "    "); err != nil {
+		return fmt.Errorf("invalid theme.json in snapshot: %w", err)
+	}
+	if err := os.WriteFile(themeFile, themeData.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write theme.json: %w", err)
+	}
+	return nil
+}
+
+// snapshotPath validates a theme name and returns its file
+func snapshotPath(name string) (string, error) {
+	if !snapshotNameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid theme name %q (use letters, digits, '.', '_' and '-')", name)
+	}
+
+	dir, err := config.GetThemesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
@@ -0,0 +1,369 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"hecate-shell/internal/material"
+	"hecate-shell/internal/templates"
+
+	"github.com/BurntSushi/toml"
+)
+
+// WCAG conformance levels
+const (
+	LevelAA  = "aa"
+	LevelAAA = "aaa"
+)
+
+// fixPasses bounds how often --fix re-checks pairs, since nudging a role can affect other pairs
+const fixPasses = 5
+
+// ContrastCheck is the contrast of one foreground/background pair in a generated file
+type ContrastCheck struct {
+	Target     string  // Template target the pair comes from
+	Foreground string  // Key of the foreground color in the generated file
+	Background string  // Key of the background color
+	FgRole     string  // Palette role behind the foreground, if the template uses one directly
+	BgRole     string  // Palette role behind the background
+	FgColor    uint32  // Generated foreground color
+	BgColor    uint32  // Generated background color
+	Ratio      float64 // WCAG contrast ratio
+	Text       bool    // Body text (4.5/7) rather than large text and UI (3/4.5)
+}
+
+// Required returns the minimum ratio the pair needs for a WCAG level
+func (c ContrastCheck) Required(level string) float64 {
+	switch {
+	case c.Text && level == LevelAAA:
+		return 7.0
+	case c.Text:
+		return 4.5
+	case level == LevelAAA:
+		return 4.5
+	}
+	return 3.0
+}
+
+// Passes reports whether the pair meets a WCAG level
+func (c ContrastCheck) Passes(level string) bool {
+	return c.Ratio >= c.Required(level)
+}
+
+// ValidateLevel checks a --level value
+func ValidateLevel(level string) error {
+	if level != LevelAA && level != LevelAAA {
+		return fmt.Errorf("invalid level %q (expected %s or %s)", level, LevelAA, LevelAAA)
+	}
+	return nil
+}
+
+// lintPair is a foreground/background pair of keys in a generated file
+type lintPair struct {
+	fg, bg string
+	text   bool
+}
+
+// lintSpecs lists the generated files that are checked and how to read them
+var lintSpecs = []struct {
+	target string
+	parse  func([]byte) (map[string]string, error)
+	pairs  func(values map[string]string) []lintPair
+}{
+	{"hecate", parseJSONColors, hecatePairs},
+	{"hecate_kitty", parseKittyColors, kittyPairs},
+	{"hecate_alacritty", parseTOMLColors, alacrittyPairs},
+}
+
+// roleRe matches a template value that is a plain palette role
+var roleRe = regexp.MustCompile(`^\{\{\s*colors\.([a-z0-9_]+)\.default\.hex\s*\}\}$`)
+
+// Lint computes the contrast of every foreground/background pair in theme.json and the
+// generated kitty and alacritty palettes. Disabled or missing targets are skipped.
+func Lint() ([]ContrastCheck, error) {
+	targets, err := templates.Targets()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]templates.Target, len(targets))
+	for _, target := range targets {
+		byName[target.Name] = target
+	}
+
+	var checks []ContrastCheck
+	for _, spec := range lintSpecs {
+		target, ok := byName[spec.target]
+		if !ok {
+			continue
+		}
+
+		output, err := os.ReadFile(target.Output)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", target.Name, err)
+		}
+		values, err := spec.parse(output)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", target.Output, err)
+		}
+
+		// The template tells which palette role each key comes from
+		roles := make(map[string]string)
+		if input, err := os.ReadFile(target.Input); err == nil {
+			if templateValues, err := spec.parse(input); err == nil {
+				for key, value := range templateValues {
+					if match := roleRe.FindStringSubmatch(value); match != nil {
+						roles[key] = match[1]
+					}
+				}
+			}
+		}
+
+		for _, pair := range spec.pairs(values) {
+			fg, fgErr := material.ArgbFromHex(values[pair.fg])
+			bg, bgErr := material.ArgbFromHex(values[pair.bg])
+			if fgErr != nil || bgErr != nil {
+				continue
+			}
+			checks = append(checks, ContrastCheck{
+				Target:     target.Name,
+				Foreground: pair.fg,
+				Background: pair.bg,
+				FgRole:     roles[pair.fg],
+				BgRole:     roles[pair.bg],
+				FgColor:    fg,
+				BgColor:    bg,
+				Ratio:      material.ContrastRatio(fg, bg),
+				Text:       pair.text,
+			})
+		}
+	}
+	return checks, nil
+}
+
+// FixContrast nudges the tone of failing foreground roles in the live palette until their
+// pairs meet level, then re-renders every target. Returns the roles that were changed.
+// A change is only kept if it reduces the number of failing pairs overall, and surface
+// roles are never moved, since they back everything else.
+func FixContrast(level string) ([]string, error) {
+	palette, err := LoadPalette()
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := Lint()
+	if err != nil {
+		return nil, err
+	}
+
+	colors := palette.Light
+	if palette.IsDark() {
+		colors = palette.Dark
+	}
+
+	changed := make(map[string]bool)
+	failures := countFailures(checks, colors, level)
+	for pass := 0; pass < fixPasses && failures > 0; pass++ {
+		progress := false
+		for _, check := range checks {
+			if check.FgRole == "" || isSurfaceRole(check.FgRole) {
+				continue
+			}
+			fg, bg := checkColors(check, colors)
+			if material.ContrastRatio(fg, bg) >= check.Required(level) {
+				continue
+			}
+
+			original := colors[check.FgRole]
+			best := original
+			for _, candidate := range nudgeTone(fg, bg, check.Required(level)) {
+				colors[check.FgRole] = material.HexFromArgb(candidate)
+				if n := countFailures(checks, colors, level); n < failures {
+					failures, best = n, colors[check.FgRole]
+				}
+			}
+			colors[check.FgRole] = best
+			if best != original {
+				changed[check.FgRole] = true
+				progress = true
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+
+	roles := make([]string, 0, len(changed))
+	for role := range changed {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	if len(roles) == 0 {
+		return nil, nil
+	}
+
+	return roles, Apply(palette)
+}
+
+// checkColors returns a pair's colors, taking palette roles from colors when known
+func checkColors(check ContrastCheck, colors map[string]string) (uint32, uint32) {
+	fg, bg := check.FgColor, check.BgColor
+	if argb, err := material.ArgbFromHex(colors[check.FgRole]); check.FgRole != "" && err == nil {
+		fg = argb
+	}
+	if argb, err := material.ArgbFromHex(colors[check.BgRole]); check.BgRole != "" && err == nil {
+		bg = argb
+	}
+	return fg, bg
+}
+
+func countFailures(checks []ContrastCheck, colors map[string]string, level string) int {
+	failures := 0
+	for _, check := range checks {
+		fg, bg := checkColors(check, colors)
+		if material.ContrastRatio(fg, bg) < check.Required(level) {
+			failures++
+		}
+	}
+	return failures
+}
+
+// nudgeTone moves a color's tone away from the background in both directions, keeping hue
+// and chroma, and returns the closest tone on each side that reaches ratio
+func nudgeTone(fg, bg uint32, ratio float64) []uint32 {
+	hct := material.HctFromArgb(fg)
+
+	var candidates []uint32
+	for _, direction := range []float64{1, -1} {
+		for tone := hct.Tone(); tone >= 0 && tone <= 100; tone += direction {
+			candidate := material.NewHct(hct.Hue(), hct.Chroma(), tone).Argb()
+			if material.ContrastRatio(candidate, bg) >= ratio {
+				candidates = append(candidates, candidate)
+				break
+			}
+		}
+	}
+	return candidates
+}
+
+func isSurfaceRole(role string) bool {
+	return strings.HasPrefix(role, "surface") || strings.HasPrefix(role, "background") ||
+		role == "inverse_surface" || role == "shadow" || role == "scrim"
+}
+
+// hecatePairs pairs every "<name>Text" key with "<name>", plus accents on surfaces
+func hecatePairs(values map[string]string) []lintPair {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []lintPair
+	for _, key := range keys {
+		if base, ok := strings.CutSuffix(key, "Text"); ok {
+			if _, ok := values[base]; ok {
+				pairs = append(pairs, lintPair{key, base, true})
+			}
+		}
+	}
+	for _, container := range []string{"surfaceContainer", "surfaceContainerHigh", "surfaceContainerHighest"} {
+		pairs = append(pairs, lintPair{"surfaceText", container, true})
+	}
+	for _, accent := range []string{"primary", "secondary", "error", "success", "warning", "info", "outline"} {
+		pairs = append(pairs, lintPair{accent, "surface", false})
+	}
+	return pairs
+}
+
+func kittyPairs(values map[string]string) []lintPair {
+	pairs := []lintPair{
+		{"foreground", "background", true},
+		{"selection_foreground", "selection_background", true},
+		{"cursor_text_color", "cursor", true},
+		{"url_color", "background", true},
+		{"color8", "background", false}, // Bright black is for dimmed text such as comments
+	}
+	for i := 1; i < 16; i++ {
+		if i != 8 {
+			pairs = append(pairs, lintPair{fmt.Sprintf("color%d", i), "background", true})
+		}
+	}
+	return pairs
+}
+
+func alacrittyPairs(values map[string]string) []lintPair {
+	pairs := []lintPair{
+		{"colors.primary.foreground", "colors.primary.background", true},
+		{"colors.selection.text", "colors.selection.background", true},
+		{"colors.cursor.text", "colors.cursor.cursor", true},
+		{"colors.bright.black", "colors.primary.background", false},
+	}
+	for _, group := range []string{"normal", "bright"} {
+		for _, color := range []string{"red", "green", "yellow", "blue", "magenta", "cyan", "white"} {
+			pairs = append(pairs, lintPair{"colors." + group + "." + color, "colors.primary.background", true})
+		}
+	}
+	return pairs
+}
+
+// parseJSONColors reads the string values of a flat JSON object
+func parseJSONColors(data []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for key, value := range raw {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		}
+	}
+	return values, nil
+}
+
+// parseKittyColors reads "key value" lines
+func parseKittyColors(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, " "); ok {
+			values[key] = strings.TrimSpace(value)
+		}
+	}
+	return values, nil
+}
+
+// parseTOMLColors reads string values of a TOML file, keyed by their dotted path
+func parseTOMLColors(data []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	var flatten func(prefix string, table map[string]interface{})
+	flatten = func(prefix string, table map[string]interface{}) {
+		for key, value := range table {
+			switch v := value.(type) {
+			case string:
+				values[prefix+key] = v
+			case map[string]interface{}:
+				flatten(prefix+key+".", v)
+			}
+		}
+	}
+	flatten("", raw)
+	return values, nil
+}
@@ -0,0 +1,95 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/material"
+)
+
+// fixedRoles are never adjusted, since they are black in every scheme
+var fixedRoles = map[string]bool{"shadow": true, "scrim": true}
+
+// applyOverrides layers theme.overrides from config.json onto a freshly generated palette.
+// Saved palettes already carry the overrides they were generated with, so this is only
+// called where colors are generated, never when a palette is re-rendered.
+func applyOverrides(p Palette) (Palette, error) {
+	settings, err := config.LoadThemeSettings()
+	if err != nil {
+		return p, err
+	}
+	if settings.Overrides == nil {
+		return p, nil
+	}
+
+	o := *settings.Overrides
+	if err := validateOverrides(o); err != nil {
+		return p, fmt.Errorf("invalid theme.overrides in config.json: %w", err)
+	}
+
+	p.Dark = overrideColors(p.Dark, o, o.Dark)
+	p.Light = overrideColors(p.Light, o, o.Light)
+	return p, nil
+}
+
+// overrideColors adjusts every role, then applies the pins for both modes and for this mode
+func overrideColors(colors map[string]string, o config.ThemeOverrides, modePins map[string]string) map[string]string {
+	out := make(map[string]string, len(colors))
+	for role, hex := range colors {
+		out[role] = hex
+		if fixedRoles[role] {
+			continue
+		}
+		if argb, err := material.ArgbFromHex(hex); err == nil {
+			out[role] = material.HexFromArgb(adjustColor(argb, o))
+		}
+	}
+
+	for _, pins := range []map[string]string{o.Roles, modePins} {
+		for role, hex := range pins {
+			argb, _ := material.ArgbFromHex(hex)
+			out[role] = material.HexFromArgb(argb | 0xff000000)
+		}
+	}
+	return out
+}
+
+// adjustColor applies the hue shift, chroma multiplier and tone bias in HCT
+func adjustColor(argb uint32, o config.ThemeOverrides) uint32 {
+	if o.HueShift == 0 && o.Chroma == nil && o.ToneBias == 0 {
+		return argb
+	}
+
+	hct := material.HctFromArgb(argb)
+	chroma := hct.Chroma()
+	if o.Chroma != nil {
+		chroma *= *o.Chroma
+	}
+	tone := math.Max(0, math.Min(100, hct.Tone()+o.ToneBias))
+	return material.NewHct(hct.Hue()+o.HueShift, chroma, tone).Argb()
+}
+
+// validateOverrides checks pinned roles and colors
+func validateOverrides(o config.ThemeOverrides) error {
+	if o.Chroma != nil && *o.Chroma < 0 {
+		return fmt.Errorf("chroma must not be negative")
+	}
+
+	known := make(map[string]bool)
+	for _, role := range material.RoleNames() {
+		known[role] = true
+	}
+
+	for _, pins := range []map[string]string{o.Roles, o.Dark, o.Light} {
+		for role, hex := range pins {
+			if !known[role] {
+				return fmt.Errorf("unknown role %q", role)
+			}
+			if _, err := material.ArgbFromHex(hex); err != nil {
+				return fmt.Errorf("role %s: %w", role, err)
+			}
+		}
+	}
+	return nil
+}
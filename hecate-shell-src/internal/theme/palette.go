@@ -0,0 +1,172 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/material"
+	"hecate-shell/internal/templates"
+)
+
+// ErrNoPalette means the live theme has no palette.json, so it can't be re-rendered
+var ErrNoPalette = errors.New("no palette for the current theme (generate one with 'hecate wallpaper <path> -g' or 'hecate theme from-color')")
+
+// Palette is every color role of a generated theme in both modes. It is saved as
+// palette.json next to theme.json so a theme can be re-rendered without extracting
+// colors again.
+type Palette struct {
+	Source string            `json:"source,omitempty"` // Seed color
+	Name   string            `json:"name,omitempty"`   // Imported scheme name, if any
+	Image  string            `json:"image,omitempty"`  // Source wallpaper, if any
+	Mode   string            `json:"mode"`             // Resolved mode: "dark" or "light"
+	Scheme string            `json:"scheme,omitempty"` // Material scheme variant
+	Dark   map[string]string `json:"dark"`             // Role name -> hex
+	Light  map[string]string `json:"light"`            // Role name -> hex
+}
+
+// newPalette builds the palette for a source color
+func newPalette(source uint32, imagePath string, variant material.Variant, isDark bool) Palette {
+	return Palette{
+		Source: material.HexFromArgb(source),
+		Image:  imagePath,
+		Mode:   modeName(isDark),
+		Scheme: string(variant),
+		Dark:   hexColors(material.NewScheme(source, variant, true).Colors()),
+		Light:  hexColors(material.NewScheme(source, variant, false).Colors()),
+	}
+}
+
+// Label describes where the palette came from: wallpaper, imported scheme or seed color
+func (p Palette) Label() string {
+	switch {
+	case p.Image != "":
+		return filepath.Base(p.Image)
+	case p.Name != "":
+		return p.Name
+	}
+	return p.Source
+}
+
+// IsDark reports whether the palette's default variant is dark
+func (p Palette) IsDark() bool {
+	return p.Mode != ModeLight
+}
+
+// Data converts the palette into template render data. Roles added to HecateShell
+// after the palette was saved are generated from its source color.
+func (p Palette) Data() (templates.Data, error) {
+	dark, err := argbColors(p.Dark)
+	if err != nil {
+		return templates.Data{}, err
+	}
+	light, err := argbColors(p.Light)
+	if err != nil {
+		return templates.Data{}, err
+	}
+	if err := p.fillMissing(dark, light); err != nil {
+		return templates.Data{}, err
+	}
+	return templates.Data{Image: p.Image, Dark: dark, Light: light, IsDark: p.IsDark()}, nil
+}
+
+// fillMissing adds any role the palette lacks, generated from its source color
+func (p Palette) fillMissing(dark, light map[string]uint32) error {
+	var missing []string
+	for _, role := range material.RoleNames() {
+		_, inDark := dark[role]
+		_, inLight := light[role]
+		if !inDark || !inLight {
+			missing = append(missing, role)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	source, err := material.ArgbFromHex(p.Source)
+	if err != nil {
+		return fmt.Errorf("palette is missing %s and has no source color: %w", missing[0], err)
+	}
+	variant, err := material.ParseVariant(p.Scheme)
+	if err != nil {
+		// Imported palettes record the scheme format instead
+		variant = material.DefaultVariant
+	}
+
+	generatedDark := material.NewScheme(source, variant, true).Colors()
+	generatedLight := material.NewScheme(source, variant, false).Colors()
+	for _, role := range missing {
+		if _, ok := dark[role]; !ok {
+			dark[role] = generatedDark[role]
+		}
+		if _, ok := light[role]; !ok {
+			light[role] = generatedLight[role]
+		}
+	}
+	return nil
+}
+
+// LoadPalette reads palette.json
+func LoadPalette() (Palette, error) {
+	var p Palette
+
+	path, err := config.GetPaletteFile()
+	if err != nil {
+		return p, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return p, ErrNoPalette
+		}
+		return p, fmt.Errorf("failed to read palette.json: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("failed to parse palette.json: %w", err)
+	}
+	return p, nil
+}
+
+// save writes palette.json
+func (p Palette) save() error {
+	path, err := config.GetPaletteFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal palette: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write palette.json: %w", err)
+	}
+	return nil
+}
+
+func hexColors(colors map[string]uint32) map[string]string {
+	out := make(map[string]string, len(colors))
+	for role, argb := range colors {
+		out[role] = material.HexFromArgb(argb)
+	}
+	return out
+}
+
+func argbColors(colors map[string]string) (map[string]uint32, error) {
+	out := make(map[string]uint32, len(colors))
+	for role, hex := range colors {
+		argb, err := material.ArgbFromHex(hex)
+		if err != nil {
+			return nil, fmt.Errorf("role %s: %w", role, err)
+		}
+		out[role] = argb
+	}
+	return out, nil
+}
@@ -0,0 +1,207 @@
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hecate-shell/internal/config"
+	"hecate-shell/internal/material"
+	"hecate-shell/internal/templates"
+)
+
+// Swatch is a named color shown in a preview
+type Swatch struct {
+	Name  string
+	Color uint32
+}
+
+// Preview is what `hecate theme preview` shows: the theme.json roles and the terminal palette
+type Preview struct {
+	Title string
+	Roles []Swatch // theme.json roles, in file order
+	ANSI  []uint32 // The 16 terminal colors from the kitty template; empty if unavailable
+}
+
+// PreviewLive previews the theme currently in use
+func PreviewLive() (Preview, error) {
+	themeFile, err := config.GetThemeFile()
+	if err != nil {
+		return Preview{}, err
+	}
+	preview, err := PreviewFile(themeFile)
+	if err != nil {
+		return Preview{}, err
+	}
+	preview.Title = "Current theme"
+
+	targets, err := templates.Targets()
+	if err != nil {
+		return Preview{}, err
+	}
+	for _, target := range targets {
+		if target.Name == "hecate_kitty" {
+			if data, err := os.ReadFile(target.Output); err == nil {
+				preview.ANSI = ansiFromKitty(data)
+			}
+		}
+	}
+	return preview, nil
+}
+
+// PreviewFile previews a theme.json file. Its terminal colors are unknown.
+func PreviewFile(path string) (Preview, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Preview{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	roles, err := swatchesFromThemeJSON(data)
+	if err != nil {
+		return Preview{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return Preview{Title: path, Roles: roles}, nil
+}
+
+// PreviewSnapshot previews a saved theme
+func PreviewSnapshot(name string) (Preview, error) {
+	snapshot, err := LoadSnapshot(name)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	roles, err := swatchesFromThemeJSON(snapshot.Theme)
+	if err != nil {
+		return Preview{}, fmt.Errorf("invalid theme.json in snapshot %s: %w", name, err)
+	}
+
+	_, kitty, err := renderPreview(snapshot.Palette)
+	if err != nil {
+		return Preview{}, err
+	}
+	return Preview{Title: name, Roles: roles, ANSI: ansiFromKitty(kitty)}, nil
+}
+
+// PreviewImage generates a scheme from an image into a temp dir and previews it,
+// leaving the live theme and config.json untouched. Always uses the built-in engine.
+func PreviewImage(imagePath string, opts Options) (Preview, error) {
+	img, err := material.LoadImage(imagePath)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	isDark := opts.Mode != ModeLight
+	if opts.Mode == ModeAuto {
+		isDark = material.AverageTone(img) < autoLightThreshold
+	}
+
+	source := material.SourceColorFromImage(img)
+	palette, err := applyOverrides(newPalette(source, imagePath, opts.scheme(), isDark))
+	if err != nil {
+		return Preview{}, err
+	}
+
+	themeData, kitty, err := renderPreview(palette)
+	if err != nil {
+		return Preview{}, err
+	}
+	roles, err := swatchesFromThemeJSON(themeData)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	title := fmt.Sprintf("%s (%s, %s, source %s)", filepath.Base(imagePath), palette.Mode, palette.Scheme, palette.Source)
+	return Preview{Title: title, Roles: roles, ANSI: ansiFromKitty(kitty)}, nil
+}
+
+// renderPreview renders the theme.json and kitty templates for a palette into a temp dir
+func renderPreview(p Palette) ([]byte, []byte, error) {
+	data, err := p.Data()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifest, err := templates.Manifest()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "hecate-preview-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	rendered := make(map[string][]byte)
+	for _, target := range manifest {
+		if target.Name != "hecate" && target.Name != "hecate_kitty" {
+			continue
+		}
+		output := filepath.Join(tmpDir, target.Name)
+		if err := target.Render(output, data); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", target.Name, err)
+		}
+		if rendered[target.Name], err = os.ReadFile(output); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if rendered["hecate"] == nil {
+		return nil, nil, fmt.Errorf("no hecate template in templates.json")
+	}
+	return rendered["hecate"], rendered["hecate_kitty"], nil
+}
+
+// swatchesFromThemeJSON reads the color entries of theme.json, keeping their order
+func swatchesFromThemeJSON(data []byte) ([]Swatch, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	var swatches []Swatch
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		hex, ok := value.(string)
+		if !ok || !strings.HasPrefix(hex, "#") {
+			continue
+		}
+		if argb, err := material.ArgbFromHex(hex); err == nil {
+			swatches = append(swatches, Swatch{Name: key, Color: argb})
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return swatches, nil
+}
+
+// ansiFromKitty reads color0-color15 from a kitty color config
+func ansiFromKitty(data []byte) []uint32 {
+	values, _ := parseKittyColors(data)
+
+	colors := make([]uint32, 0, 16)
+	for i := 0; i < 16; i++ {
+		argb, err := material.ArgbFromHex(values[fmt.Sprintf("color%d", i)])
+		if err != nil {
+			return nil
+		}
+		colors = append(colors, argb)
+	}
+	return colors
+}
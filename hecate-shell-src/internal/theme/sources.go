@@ -0,0 +1,121 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"hecate-shell/internal/material"
+	"hecate-shell/internal/templates"
+)
+
+// themeKeyRe matches a theme.json key filled from a single role in the hecate template
+var themeKeyRe = regexp.MustCompile(`"(\w+)"\s*:\s*"\{\{\s*colors\.(\w+)\.default\.hex\s*\}\}"`)
+
+// PaletteFromColor builds the palette a seed color would generate, without applying it
+func PaletteFromColor(hex string, opts Options) (Palette, error) {
+	source, err := material.ArgbFromHex(hex)
+	if err != nil {
+		return Palette{}, err
+	}
+	// Seeds are always opaque
+	source |= 0xff000000
+
+	isDark := opts.Mode != ModeLight
+	if opts.Mode == ModeAuto {
+		isDark = material.HctFromArgb(source).Tone() < autoLightThreshold
+	}
+	return applyOverrides(newPalette(source, "", opts.scheme(), isDark))
+}
+
+// PaletteFromFile reads a palette.json, a saved theme or a theme.json. A theme.json only
+// has some roles in one mode: its colors are used for the mode its background implies,
+// and everything else is generated from its primary color.
+func PaletteFromFile(path string) (Palette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Palette{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return Palette{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	switch {
+	case keys["dark"] != nil && keys["light"] != nil:
+		var p Palette
+		if err := json.Unmarshal(data, &p); err != nil {
+			return Palette{}, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return p, nil
+	case keys["palette"] != nil:
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return Palette{}, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return snapshot.Palette, nil
+	}
+	return paletteFromThemeJSON(keys)
+}
+
+// paletteFromThemeJSON maps theme.json keys back to the roles the hecate template fills them from
+func paletteFromThemeJSON(keys map[string]json.RawMessage) (Palette, error) {
+	roles, err := themeJSONRoles()
+	if err != nil {
+		return Palette{}, err
+	}
+
+	colors := make(map[string]string)
+	for key, raw := range keys {
+		role, ok := roles[key]
+		if !ok {
+			continue
+		}
+		var hex string
+		if err := json.Unmarshal(raw, &hex); err != nil {
+			return Palette{}, fmt.Errorf("%s is not a color", key)
+		}
+		if _, err := material.ArgbFromHex(hex); err != nil {
+			return Palette{}, fmt.Errorf("%s: %w", key, err)
+		}
+		colors[role] = hex
+	}
+
+	if colors["primary"] == "" || colors["background"] == "" {
+		return Palette{}, fmt.Errorf("not a theme.json, palette.json or saved theme: primary and background are required")
+	}
+
+	background, _ := material.ArgbFromHex(colors["background"])
+	p := Palette{Source: colors["primary"], Mode: ModeDark, Dark: colors, Light: map[string]string{}}
+	if material.HctFromArgb(background).Tone() >= autoLightThreshold {
+		p.Mode = ModeLight
+		p.Dark, p.Light = p.Light, p.Dark
+	}
+	return p, nil
+}
+
+// themeJSONRoles reads the hecate template to find which role fills each theme.json key
+func themeJSONRoles() (map[string]string, error) {
+	manifest, err := templates.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, target := range manifest {
+		if target.Name != "hecate" {
+			continue
+		}
+		src, err := os.ReadFile(target.Input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read hecate template: %w", err)
+		}
+		roles := make(map[string]string)
+		for _, m := range themeKeyRe.FindAllStringSubmatch(string(src), -1) {
+			roles[m[1]] = m[2]
+		}
+		return roles, nil
+	}
+	return nil, fmt.Errorf("no hecate template in templates.json")
+}
@@ -0,0 +1,247 @@
+package theme
+
+import (
+	"fmt"
+
+	"hecate-shell/internal/gtk"
+	"hecate-shell/internal/material"
+	"hecate-shell/internal/matugen"
+	"hecate-shell/internal/templates"
+	"hecate-shell/internal/vscode"
+)
+
+// Color generation backends
+const (
+	BackendNative  = "native"  // Built-in Material You engine
+	BackendMatugen = "matugen" // External matugen binary
+)
+
+// Scheme modes
+const (
+	ModeDark  = "dark"
+	ModeLight = "light"
+	ModeAuto  = "auto" // Light for bright wallpapers, dark otherwise
+)
+
+// autoLightThreshold is the tone above which auto mode picks light
+const autoLightThreshold = 50.0
+
+// Options controls how a theme is generated
+type Options struct {
+	Backend string
+	Mode    string
+	Scheme  material.Variant
+}
+
+// ValidateMode checks a --mode value
+func ValidateMode(mode string) error {
+	switch mode {
+	case ModeDark, ModeLight, ModeAuto:
+		return nil
+	}
+	return fmt.Errorf("invalid mode %q (expected %s, %s or %s)", mode, ModeDark, ModeLight, ModeAuto)
+}
+
+// GenerateFromImage extracts a color scheme from an image and renders every template target
+func GenerateFromImage(imagePath string, opts Options) error {
+	if err := validateBackend(opts.Backend); err != nil {
+		return err
+	}
+
+	img, err := material.LoadImage(imagePath)
+	if err != nil {
+		return err
+	}
+
+	isDark := opts.Mode != ModeLight
+	if opts.Mode == ModeAuto {
+		tone := material.AverageTone(img)
+		isDark = tone < autoLightThreshold
+		fmt.Printf("Auto mode picked %s (average wallpaper tone %.0f)\n", modeName(isDark), tone)
+	}
+
+	if opts.Backend == BackendMatugen {
+		if err := runMatugen("image", imagePath, imagePath, opts.scheme(), isDark); err != nil {
+			return err
+		}
+	} else {
+		source := material.SourceColorFromImage(img)
+		fmt.Printf("Source color: %s\n", material.HexFromArgb(source))
+		if err := renderScheme(source, imagePath, opts.scheme(), isDark); err != nil {
+			return err
+		}
+	}
+
+	setUITheme(isDark)
+	return nil
+}
+
+// GenerateFromColor builds a color scheme from a single seed color and renders every template target
+func GenerateFromColor(hex string, opts Options) error {
+	if err := validateBackend(opts.Backend); err != nil {
+		return err
+	}
+
+	source, err := material.ArgbFromHex(hex)
+	if err != nil {
+		return err
+	}
+	// Seeds are always opaque
+	source |= 0xff000000
+
+	isDark := opts.Mode != ModeLight
+	if opts.Mode == ModeAuto {
+		tone := material.HctFromArgb(source).Tone()
+		isDark = tone < autoLightThreshold
+		fmt.Printf("Auto mode picked %s (seed tone %.0f)\n", modeName(isDark), tone)
+	}
+
+	if opts.Backend == BackendMatugen {
+		if err := runMatugen("color", material.HexFromArgb(source), "", opts.scheme(), isDark); err != nil {
+			return err
+		}
+	} else {
+		if err := renderScheme(source, "", opts.scheme(), isDark); err != nil {
+			return err
+		}
+	}
+
+	setUITheme(isDark)
+	return nil
+}
+
+func validateBackend(backend string) error {
+	if backend != BackendNative && backend != BackendMatugen && backend != "" {
+		return fmt.Errorf("unknown theme backend: %s (expected %q or %q)", backend, BackendNative, BackendMatugen)
+	}
+	return nil
+}
+
+// setUITheme updates VSCode, which picks its base widget colors from the extension's uiTheme,
+// and GTK's dark preference if the GTK colors were rendered
+func setUITheme(isDark bool) {
+	if err := vscode.SetUITheme(isDark); err != nil {
+		fmt.Printf("Warning: failed to update VSCode theme type: %v\n", err)
+	}
+
+	rendered, err := templates.Rendered()
+	if err != nil {
+		fmt.Printf("Warning: failed to set GTK color scheme: %v\n", err)
+		return
+	}
+	for _, target := range rendered {
+		if target.Name == "hecate_gtk" || target.Name == "hecate_gtk3" {
+			if err := gtk.SetColorScheme(isDark); err != nil {
+				fmt.Printf("Warning: failed to set GTK color scheme: %v\n", err)
+			}
+			return
+		}
+	}
+}
+
+// scheme returns the selected variant, defaulting to fidelity
+func (o Options) scheme() material.Variant {
+	if o.Scheme == "" {
+		return material.DefaultVariant
+	}
+	return o.Scheme
+}
+
+func modeName(isDark bool) string {
+	if isDark {
+		return ModeDark
+	}
+	return ModeLight
+}
+
+// Apply renders every template target from a saved palette, without extracting colors
+func Apply(p Palette) error {
+	if err := render(p); err != nil {
+		return err
+	}
+	setUITheme(p.IsDark())
+	return nil
+}
+
+// renderScheme builds the dark and light schemes for a source color and renders all targets
+func renderScheme(source uint32, imagePath string, variant material.Variant, isDark bool) error {
+	p, err := applyOverrides(newPalette(source, imagePath, variant, isDark))
+	if err != nil {
+		return err
+	}
+	return render(p)
+}
+
+// runMatugen generates the schemes with matugen, which also renders the user's own matugen
+// templates, and renders every target from its colors. HecateShell renders its targets
+// itself because matugen can't generate the terminal palette they use.
+func runMatugen(sourceType, source, imagePath string, variant material.Variant, isDark bool) error {
+	dark, light, err := matugen.RunMatugen(sourceType, source, matugen.Options{Mode: modeName(isDark), Scheme: string(variant)})
+	if err != nil {
+		return err
+	}
+	if err := addTerminalColors(dark, light, variant); err != nil {
+		return err
+	}
+
+	p, err := applyOverrides(Palette{
+		Source: dark["source_color"],
+		Image:  imagePath,
+		Mode:   modeName(isDark),
+		Scheme: string(variant),
+		Dark:   dark,
+		Light:  light,
+	})
+	if err != nil {
+		return err
+	}
+	return render(p)
+}
+
+// addTerminalColors adds the terminal palette of the built-in engine to matugen's colors,
+// derived from the same source color
+func addTerminalColors(dark, light map[string]string, variant material.Variant) error {
+	source, err := material.ArgbFromHex(dark["source_color"])
+	if err != nil {
+		return fmt.Errorf("invalid source color from matugen: %w", err)
+	}
+	for _, mode := range []struct {
+		colors map[string]string
+		isDark bool
+	}{{dark, true}, {light, false}} {
+		generated := material.NewScheme(source, variant, mode.isDark).Colors()
+		for _, role := range material.TerminalRoles {
+			if _, ok := mode.colors[role]; !ok {
+				mode.colors[role] = material.HexFromArgb(generated[role])
+			}
+		}
+	}
+	return nil
+}
+
+// render renders all enabled targets from a palette and saves it as palette.json
+func render(p Palette) error {
+	data, err := p.Data()
+	if err != nil {
+		return err
+	}
+
+	targets, err := templates.Targets()
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		if target.Name == "hecate_gtk3" && target.Enabled {
+			// gtk.css used to be overwritten by this target; it imports the colors now
+			if err := gtk.MigrateGTK3CSS(); err != nil {
+				fmt.Printf("Warning: failed to migrate GTK3 gtk.css: %v\n", err)
+			}
+		}
+	}
+
+	if err := templates.RenderAll(targets, data); err != nil {
+		fmt.Printf("Warning: some templates failed to render:\n%v\n", err)
+	}
+	return p.save()
+}
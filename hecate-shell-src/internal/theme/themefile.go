@@ -0,0 +1,66 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"hecate-shell/internal/config"
+)
+
+// Theme is theme.json, the colors the shell and niri read
+type Theme struct {
+	Name string `json:"name"`
+
+	Primary            string `json:"primary"`
+	PrimaryText        string `json:"primaryText"`
+	PrimaryContainer   string `json:"primaryContainer"`
+	Secondary          string `json:"secondary"`
+	SecondaryText      string `json:"secondaryText"`
+	Surface            string `json:"surface"`
+	SurfaceText        string `json:"surfaceText"`
+	SurfaceVariant     string `json:"surfaceVariant"`
+	SurfaceVariantText string `json:"surfaceVariantText"`
+	SurfaceTint        string `json:"surfaceTint"`
+	Background         string `json:"background"`
+	BackgroundText     string `json:"backgroundText"`
+	Outline            string `json:"outline"`
+
+	SurfaceContainer        string `json:"surfaceContainer"`
+	SurfaceContainerHigh    string `json:"surfaceContainerHigh"`
+	SurfaceContainerHighest string `json:"surfaceContainerHighest"`
+
+	// Semantic colors, each with text and container colors from the same palette
+	Error            string `json:"error"`
+	ErrorText        string `json:"errorText"`
+	ErrorContainer   string `json:"errorContainer"`
+	Success          string `json:"success"`
+	SuccessText      string `json:"successText"`
+	SuccessContainer string `json:"successContainer"`
+	Warning          string `json:"warning"`
+	WarningText      string `json:"warningText"`
+	WarningContainer string `json:"warningContainer"`
+	Info             string `json:"info"`
+	InfoText         string `json:"infoText"`
+	InfoContainer    string `json:"infoContainer"`
+}
+
+// LoadTheme reads theme.json. Keys an older theme.json lacks are left empty.
+func LoadTheme() (Theme, error) {
+	var t Theme
+
+	path, err := config.GetThemeFile()
+	if err != nil {
+		return t, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return t, fmt.Errorf("failed to read theme.json: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, fmt.Errorf("failed to parse theme.json: %w", err)
+	}
+	return t, nil
+}
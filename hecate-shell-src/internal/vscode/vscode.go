@@ -50,7 +50,7 @@ func InstallThemeExtension() error {
 	}
 
 	// Write package.json
-	if err := writePackageJson(themeDir); err != nil {
+	if err := writePackageJson(themeDir, true); err != nil {
 		return fmt.Errorf("failed to write package.json: %w", err)
 	}
 
@@ -62,8 +62,24 @@ func InstallThemeExtension() error {
 	return nil
 }
 
+// SetUITheme switches the installed extension between a dark and light base theme,
+// so VSCode's built-in widget colors match the generated scheme
+func SetUITheme(isDark bool) error {
+	if !IsInstalled() {
+		return nil
+	}
+
+	themeDir := filepath.Join(os.Getenv("HOME"), ".vscode", "extensions", extensionDir)
+	return writePackageJson(themeDir, isDark)
+}
+
 // writePackageJson writes the extension manifest
-func writePackageJson(themeDir string) error {
+func writePackageJson(themeDir string, isDark bool) error {
+	uiTheme := "vs-dark"
+	if !isDark {
+		uiTheme = "vs"
+	}
+
 	packageJson := map[string]interface{}{
 		"name":        "hecate-theme",
 		"displayName": "HecateShell Theme",
@@ -78,7 +94,7 @@ func writePackageJson(themeDir string) error {
 			"themes": []map[string]string{
 				{
 					"label":   "HecateShell Dark",
-					"uiTheme": "vs-dark",
+					"uiTheme": uiTheme,
 					"path":    "./themes/hecate-dark.json",
 				},
 			},